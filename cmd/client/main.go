@@ -185,7 +185,7 @@ func main() {
 	}
 
 	go func() {
-		cmd := make(chan os.Signal)
+		cmd := make(chan os.Signal, 1)
 		signal.Notify(cmd, syscall.SIGINT, syscall.SIGTERM)
 		log.Debug((<-cmd).String())
 		close(closeCh)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+var (
+	// target - адрес GRPC-сервера.
+	target = flag.String("target", "127.0.0.1:61614", "grpc server port")
+
+	// reason - причина (unmatched, malformed), пусто - все.
+	reason = flag.String("reason", "", "dead letter reason (unmatched or malformed), empty - all")
+
+	// templates - идентификаторы шаблонов через запятую, пусто - все.
+	templates = flag.String("templates", "", "comma separated template ids, empty - all")
+
+	// nets - сети отправителей через запятую (CIDR), пусто - все.
+	nets = flag.String("nets", "", "comma separated source networks (CIDR), empty - all")
+)
+
+func init() {
+	flag.Parse()
+}
+
+// split - разделить список значений через запятую.
+func split(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// Вывод сообщений, которые сервис не смог преобразовать в события, по мере их получения.
+func main() {
+	rq := &pb.DeadLetterRequest{
+		ClientName: "deadletters",
+		Templates:  split(*templates),
+		Nets:       split(*nets),
+	}
+	if len(*reason) != 0 {
+		r, ok := pb.DeadLetterReason_value[strings.ToUpper((*reason)[:1])+(*reason)[1:]]
+		if !ok {
+			log.Fatalf("dead letter reason \"%s\" are unknown", *reason)
+		}
+		rq.Reasons = append(rq.Reasons, pb.DeadLetterReason(r))
+	}
+
+	conn, err := grpc.Dial(*target, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("connect to syslog-catcher server failed - %v", err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewSyslogCatcherClient(conn).DeadLetters(context.Background(), rq)
+	if err != nil {
+		log.Fatalf("subscribe to dead letters failed - %v", err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatalf("recv err - %v", err)
+		}
+		fmt.Printf("%s %s %s template=%s err=%q\n    %s\n", time.Unix(0, msg.GetTimestamp()).Format(time.RFC3339),
+			msg.GetSource(), msg.GetReason(), msg.GetTemplate(), msg.GetError(), msg.GetText())
+	}
+}
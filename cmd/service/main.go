@@ -33,9 +33,18 @@ func main() {
 
 	go service.Serve()
 
-	cmd := make(chan os.Signal)
-	signal.Notify(cmd, syscall.SIGINT, syscall.SIGTERM)
-	log.Debug((<-cmd).String())
+	cmd := make(chan os.Signal, 1)
+	signal.Notify(cmd, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range cmd {
+		log.Debug(sig.String())
+		if sig != syscall.SIGHUP {
+			break
+		}
+		// SIGHUP - перечитать справочник устройств.
+		if err := service.Reload(); err != nil {
+			log.Errorf("reload failed - %v", err)
+		}
+	}
 
 	service.Close()
 }
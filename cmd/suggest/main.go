@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+var (
+	// target - адрес GRPC-сервера.
+	target = flag.String("target", "127.0.0.1:61614", "grpc server port")
+
+	// minCount - минимальное количество сообщений группы.
+	minCount = flag.Uint64("min", 1, "min count of messages in cluster")
+
+	// limit - максимальное количество групп.
+	limit = flag.Uint("limit", 20, "max count of clusters (0 - all)")
+
+	// verbose - выводить структуру и примеры сообщений группы.
+	verbose = flag.Bool("v", false, "print cluster pattern and message examples")
+)
+
+func init() {
+	flag.Parse()
+}
+
+// Вывод предлагаемых шаблонов для сообщений, не совпавших ни с одним шаблоном сервиса,
+// в формате списка templates конфигурации.
+func main() {
+	conn, err := grpc.Dial(*target, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("connect to syslog-catcher server failed - %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rs, err := pb.NewSyslogCatcherClient(conn).Suggestions(ctx, &pb.SuggestRequest{
+		MinCount: *minCount,
+		Limit:    uint32(*limit),
+	})
+	if err != nil {
+		log.Fatalf("get suggestions failed - %v", err)
+	}
+
+	fmt.Println("templates:")
+	for _, v := range rs.GetSuggestions() {
+		fmt.Printf("  # %d messages\n", v.GetCount())
+		if *verbose {
+			fmt.Printf("  # pattern: %s\n", v.GetPattern())
+			for _, e := range v.GetExamples() {
+				fmt.Printf("  # example: %s\n", e)
+			}
+		}
+		fmt.Printf("  - %q\n", v.GetTemplate())
+	}
+}
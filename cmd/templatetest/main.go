@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
+	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/templatetest"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// cfile - путь к файлу конфигурации сервиса.
+	cfile = flag.String("config", "service_config.yml", "service configuration file path")
+
+	// listener - номер обработчика входящих сообщений (с 1), шаблоны которого проверяются.
+	listener = flag.Int("listener", 1, "syslog listener number (from 1)")
+
+	// input - файл примеров сообщений (по одному в строке, "-" - стандартный ввод).
+	input = flag.String("input", "-", "sample messages file path (- for stdin)")
+
+	// source - адрес отправителя сообщений.
+	source = flag.String("source", "127.0.0.1", "sample messages source address")
+
+	// golden - эталонный файл результатов.
+	golden = flag.String("golden", "", "golden file path to compare results with")
+
+	// update - перезаписать эталонный файл результатами обработки.
+	update = flag.Bool("update", false, "write results to golden file")
+)
+
+func init() {
+	flag.Parse()
+}
+
+// Проверка шаблонов обработчика на примерах сообщений: вывод совпавшего шаблона и события
+// для каждого примера, либо сравнение результатов с эталонным файлом.
+func main() {
+	cfg, err := config.ParseFile(*cfile)
+	if err != nil {
+		log.Fatalf("read configuration failed - %v", err)
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		log.Fatalf("read configuration failed - %v", err)
+	}
+	p, err := catcher.NewListenerParser(cfg, *listener-1)
+	if err != nil {
+		log.Fatalf("create parser failed - %v", err)
+	}
+
+	var samples io.Reader = os.Stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			log.Fatalf("open samples failed - %v", err)
+		}
+		defer f.Close()
+		samples = f
+	}
+	results, err := templatetest.Run(p, samples, *source, loc)
+	if err != nil {
+		log.Fatalf("run samples failed - %v", err)
+	}
+
+	switch {
+	case *update:
+		if len(*golden) == 0 {
+			log.Fatalf("golden file path are not set")
+		}
+		f, err := os.Create(*golden)
+		if err != nil {
+			log.Fatalf("create golden file failed - %v", err)
+		}
+		if err := templatetest.WriteGolden(f, results); err != nil {
+			f.Close()
+			log.Fatalf("write golden file failed - %v", err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("write golden file failed - %v", err)
+		}
+		fmt.Printf("%d results written to %s\n", len(results), *golden)
+	case len(*golden) != 0:
+		f, err := os.Open(*golden)
+		if err != nil {
+			log.Fatalf("open golden file failed - %v", err)
+		}
+		diff, err := templatetest.CompareGolden(f, results)
+		f.Close()
+		if err != nil {
+			log.Fatalf("compare golden file failed - %v", err)
+		}
+		for _, v := range diff {
+			fmt.Println(v)
+		}
+		fmt.Printf("%d samples, %d mismatches\n", len(results), len(diff))
+		if len(diff) != 0 {
+			os.Exit(1)
+		}
+	default:
+		templates := make(map[string]string)
+		for _, v := range p.Stats() {
+			templates[v.ID] = v.Template
+		}
+		for _, r := range results {
+			fmt.Printf("#%d %s\n", r.Line, r.Text)
+			if len(r.Template) != 0 {
+				fmt.Printf("  template: %s %q\n", r.Template, templates[r.Template])
+			}
+			switch r.Result {
+			case templatetest.ResultMatched:
+				fmt.Printf("  event:    %s\n", r.Event.String())
+			default:
+				fmt.Printf("  %s: %s\n", r.Result, r.Error)
+			}
+		}
+	}
+}
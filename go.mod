@@ -6,6 +6,7 @@ require (
 	github.com/golang/protobuf v1.3.2
 	github.com/sirupsen/logrus v1.4.2
 	golang.org/x/net v0.0.0-20191002035440-2ec189313ef0
+	golang.org/x/sys v0.0.0-20190422165155-953cdadca894
 	google.golang.org/grpc v1.24.0
 	gopkg.in/yaml.v2 v2.2.4
 )
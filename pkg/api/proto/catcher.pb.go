@@ -6,11 +6,24 @@
 Package catcher is a generated protocol buffer package.
 
 It is generated from these files:
+
 	catcher.proto
 
 It has these top-level messages:
+
 	EventRequest
 	Event
+	Device
+	InterfaceID
+	SyslogHeader
+	SuggestRequest
+	SuggestResponse
+	Suggestion
+	DeadLetterRequest
+	DeadLetter
+	TemplateStatsRequest
+	TemplateStatsResponse
+	TemplateStats
 */
 package catcher
 
@@ -38,10 +51,11 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type EventType int32
 
 const (
-	EventType_Unknown        EventType = 0
-	EventType_PortUp         EventType = 1
-	EventType_PortDown       EventType = 2
-	EventType_PortLoopDetect EventType = 3
+	EventType_Unknown         EventType = 0
+	EventType_PortUp          EventType = 1
+	EventType_PortDown        EventType = 2
+	EventType_PortLoopDetect  EventType = 3
+	EventType_StormSuppressed EventType = 4
 )
 
 var EventType_name = map[int32]string{
@@ -49,12 +63,14 @@ var EventType_name = map[int32]string{
 	1: "PortUp",
 	2: "PortDown",
 	3: "PortLoopDetect",
+	4: "StormSuppressed",
 }
 var EventType_value = map[string]int32{
-	"Unknown":        0,
-	"PortUp":         1,
-	"PortDown":       2,
-	"PortLoopDetect": 3,
+	"Unknown":         0,
+	"PortUp":          1,
+	"PortDown":        2,
+	"PortLoopDetect":  3,
+	"StormSuppressed": 4,
 }
 
 func (x EventType) String() string {
@@ -70,19 +86,49 @@ const (
 	PortSpeed_Speed100Mb   PortSpeed = 1
 	PortSpeed_Speed10Mb    PortSpeed = 2
 	PortSpeed_Speed1Gb     PortSpeed = 3
+	PortSpeed_Speed2500Mb  PortSpeed = 4
+	PortSpeed_Speed5Gb     PortSpeed = 5
+	PortSpeed_Speed10Gb    PortSpeed = 6
+	PortSpeed_Speed25Gb    PortSpeed = 7
+	PortSpeed_Speed40Gb    PortSpeed = 8
+	PortSpeed_Speed50Gb    PortSpeed = 9
+	PortSpeed_Speed100Gb   PortSpeed = 10
+	PortSpeed_Speed200Gb   PortSpeed = 11
+	PortSpeed_Speed400Gb   PortSpeed = 12
+	PortSpeed_SpeedAuto    PortSpeed = 13
 )
 
 var PortSpeed_name = map[int32]string{
-	0: "UnknownSpeed",
-	1: "Speed100Mb",
-	2: "Speed10Mb",
-	3: "Speed1Gb",
+	0:  "UnknownSpeed",
+	1:  "Speed100Mb",
+	2:  "Speed10Mb",
+	3:  "Speed1Gb",
+	4:  "Speed2500Mb",
+	5:  "Speed5Gb",
+	6:  "Speed10Gb",
+	7:  "Speed25Gb",
+	8:  "Speed40Gb",
+	9:  "Speed50Gb",
+	10: "Speed100Gb",
+	11: "Speed200Gb",
+	12: "Speed400Gb",
+	13: "SpeedAuto",
 }
 var PortSpeed_value = map[string]int32{
 	"UnknownSpeed": 0,
 	"Speed100Mb":   1,
 	"Speed10Mb":    2,
 	"Speed1Gb":     3,
+	"Speed2500Mb":  4,
+	"Speed5Gb":     5,
+	"Speed10Gb":    6,
+	"Speed25Gb":    7,
+	"Speed40Gb":    8,
+	"Speed50Gb":    9,
+	"Speed100Gb":   10,
+	"Speed200Gb":   11,
+	"Speed400Gb":   12,
+	"SpeedAuto":    13,
 }
 
 func (x PortSpeed) String() string {
@@ -115,11 +161,36 @@ func (x PortDuplex) String() string {
 }
 func (PortDuplex) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
+// DeadLetterReason - parse failure reason consts.
+type DeadLetterReason int32
+
+const (
+	DeadLetterReason_Unmatched DeadLetterReason = 0
+	DeadLetterReason_Malformed DeadLetterReason = 1
+)
+
+var DeadLetterReason_name = map[int32]string{
+	0: "Unmatched",
+	1: "Malformed",
+}
+var DeadLetterReason_value = map[string]int32{
+	"Unmatched": 0,
+	"Malformed": 1,
+}
+
+func (x DeadLetterReason) String() string {
+	return proto.EnumName(DeadLetterReason_name, int32(x))
+}
+func (DeadLetterReason) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
 // EventRequest - subscription parameters.
 type EventRequest struct {
 	ClientName string      `protobuf:"bytes,1,opt,name=ClientName,json=clientName" json:"ClientName,omitempty"`
 	Events     []EventType `protobuf:"varint,2,rep,packed,name=Events,json=events,enum=catcher.EventType" json:"Events,omitempty"`
 	Nets       []string    `protobuf:"bytes,3,rep,name=Nets,json=nets" json:"Nets,omitempty"`
+	TypeNames  []string    `protobuf:"bytes,4,rep,name=TypeNames,json=typeNames" json:"TypeNames,omitempty"`
+	Sites      []string    `protobuf:"bytes,5,rep,name=Sites,json=sites" json:"Sites,omitempty"`
+	Roles      []string    `protobuf:"bytes,6,rep,name=Roles,json=roles" json:"Roles,omitempty"`
 }
 
 func (m *EventRequest) Reset()                    { *m = EventRequest{} }
@@ -148,13 +219,45 @@ func (m *EventRequest) GetNets() []string {
 	return nil
 }
 
+func (m *EventRequest) GetTypeNames() []string {
+	if m != nil {
+		return m.TypeNames
+	}
+	return nil
+}
+
+func (m *EventRequest) GetSites() []string {
+	if m != nil {
+		return m.Sites
+	}
+	return nil
+}
+
+func (m *EventRequest) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 // Event - parsed syslog event.
 type Event struct {
-	Type   EventType  `protobuf:"varint,1,opt,name=Type,json=type,enum=catcher.EventType" json:"Type,omitempty"`
-	Host   string     `protobuf:"bytes,2,opt,name=Host,json=host" json:"Host,omitempty"`
-	Port   uint32     `protobuf:"varint,3,opt,name=Port,json=port" json:"Port,omitempty"`
-	Speed  PortSpeed  `protobuf:"varint,4,opt,name=Speed,json=speed,enum=catcher.PortSpeed" json:"Speed,omitempty"`
-	Duplex PortDuplex `protobuf:"varint,5,opt,name=Duplex,json=duplex,enum=catcher.PortDuplex" json:"Duplex,omitempty"`
+	Type        EventType         `protobuf:"varint,1,opt,name=Type,json=type,enum=catcher.EventType" json:"Type,omitempty"`
+	Host        string            `protobuf:"bytes,2,opt,name=Host,json=host" json:"Host,omitempty"`
+	Port        uint32            `protobuf:"varint,3,opt,name=Port,json=port" json:"Port,omitempty"`
+	Speed       PortSpeed         `protobuf:"varint,4,opt,name=Speed,json=speed,enum=catcher.PortSpeed" json:"Speed,omitempty"`
+	Duplex      PortDuplex        `protobuf:"varint,5,opt,name=Duplex,json=duplex,enum=catcher.PortDuplex" json:"Duplex,omitempty"`
+	Header      *SyslogHeader     `protobuf:"bytes,6,opt,name=Header,json=header" json:"Header,omitempty"`
+	Peer        string            `protobuf:"bytes,7,opt,name=Peer,json=peer" json:"Peer,omitempty"`
+	Suppressed  uint64            `protobuf:"varint,8,opt,name=Suppressed,json=suppressed" json:"Suppressed,omitempty"`
+	TypeName    string            `protobuf:"bytes,9,opt,name=TypeName,json=typeName" json:"TypeName,omitempty"`
+	Severity    uint32            `protobuf:"varint,10,opt,name=Severity,json=severity" json:"Severity,omitempty"`
+	Attributes  map[string]string `protobuf:"bytes,11,rep,name=Attributes,json=attributes" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SpeedMbps   uint64            `protobuf:"varint,12,opt,name=SpeedMbps,json=speedMbps" json:"SpeedMbps,omitempty"`
+	Interface   string            `protobuf:"bytes,13,opt,name=Interface,json=interface" json:"Interface,omitempty"`
+	InterfaceID *InterfaceID      `protobuf:"bytes,14,opt,name=InterfaceID,json=interfaceID" json:"InterfaceID,omitempty"`
+	Template    string            `protobuf:"bytes,15,opt,name=Template,json=template" json:"Template,omitempty"`
+	Device      *Device           `protobuf:"bytes,16,opt,name=Device,json=device" json:"Device,omitempty"`
 }
 
 func (m *Event) Reset()                    { *m = Event{} }
@@ -197,12 +300,560 @@ func (m *Event) GetDuplex() PortDuplex {
 	return PortDuplex_UnknownDuplex
 }
 
+func (m *Event) GetHeader() *SyslogHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Event) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *Event) GetSuppressed() uint64 {
+	if m != nil {
+		return m.Suppressed
+	}
+	return 0
+}
+
+func (m *Event) GetTypeName() string {
+	if m != nil {
+		return m.TypeName
+	}
+	return ""
+}
+
+func (m *Event) GetSeverity() uint32 {
+	if m != nil {
+		return m.Severity
+	}
+	return 0
+}
+
+func (m *Event) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *Event) GetSpeedMbps() uint64 {
+	if m != nil {
+		return m.SpeedMbps
+	}
+	return 0
+}
+
+func (m *Event) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *Event) GetInterfaceID() *InterfaceID {
+	if m != nil {
+		return m.InterfaceID
+	}
+	return nil
+}
+
+func (m *Event) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *Event) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+// Device - device data from the device inventory.
+type Device struct {
+	Hostname string `protobuf:"bytes,1,opt,name=Hostname,json=hostname" json:"Hostname,omitempty"`
+	Site     string `protobuf:"bytes,2,opt,name=Site,json=site" json:"Site,omitempty"`
+	Vendor   string `protobuf:"bytes,3,opt,name=Vendor,json=vendor" json:"Vendor,omitempty"`
+	Model    string `protobuf:"bytes,4,opt,name=Model,json=model" json:"Model,omitempty"`
+	Role     string `protobuf:"bytes,5,opt,name=Role,json=role" json:"Role,omitempty"`
+}
+
+func (m *Device) Reset()                    { *m = Device{} }
+func (m *Device) String() string            { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()               {}
+func (*Device) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Device) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *Device) GetSite() string {
+	if m != nil {
+		return m.Site
+	}
+	return ""
+}
+
+func (m *Device) GetVendor() string {
+	if m != nil {
+		return m.Vendor
+	}
+	return ""
+}
+
+func (m *Device) GetModel() string {
+	if m != nil {
+		return m.Model
+	}
+	return ""
+}
+
+func (m *Device) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+// InterfaceID - parsed interface name components.
+type InterfaceID struct {
+	Type   string `protobuf:"bytes,1,opt,name=Type,json=type" json:"Type,omitempty"`
+	Slot   uint32 `protobuf:"varint,2,opt,name=Slot,json=slot" json:"Slot,omitempty"`
+	Module uint32 `protobuf:"varint,3,opt,name=Module,json=module" json:"Module,omitempty"`
+	Port   uint32 `protobuf:"varint,4,opt,name=Port,json=port" json:"Port,omitempty"`
+}
+
+func (m *InterfaceID) Reset()                    { *m = InterfaceID{} }
+func (m *InterfaceID) String() string            { return proto.CompactTextString(m) }
+func (*InterfaceID) ProtoMessage()               {}
+func (*InterfaceID) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *InterfaceID) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *InterfaceID) GetSlot() uint32 {
+	if m != nil {
+		return m.Slot
+	}
+	return 0
+}
+
+func (m *InterfaceID) GetModule() uint32 {
+	if m != nil {
+		return m.Module
+	}
+	return 0
+}
+
+func (m *InterfaceID) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+// SyslogHeader - decoded syslog message header.
+type SyslogHeader struct {
+	Priority  uint32 `protobuf:"varint,1,opt,name=Priority,json=priority" json:"Priority,omitempty"`
+	Facility  uint32 `protobuf:"varint,2,opt,name=Facility,json=facility" json:"Facility,omitempty"`
+	Severity  uint32 `protobuf:"varint,3,opt,name=Severity,json=severity" json:"Severity,omitempty"`
+	Timestamp int64  `protobuf:"varint,4,opt,name=Timestamp,json=timestamp" json:"Timestamp,omitempty"`
+	Hostname  string `protobuf:"bytes,5,opt,name=Hostname,json=hostname" json:"Hostname,omitempty"`
+	AppName   string `protobuf:"bytes,6,opt,name=AppName,json=appName" json:"AppName,omitempty"`
+	ProcID    string `protobuf:"bytes,7,opt,name=ProcID,json=procID" json:"ProcID,omitempty"`
+	MsgID     string `protobuf:"bytes,8,opt,name=MsgID,json=msgID" json:"MsgID,omitempty"`
+}
+
+func (m *SyslogHeader) Reset()                    { *m = SyslogHeader{} }
+func (m *SyslogHeader) String() string            { return proto.CompactTextString(m) }
+func (*SyslogHeader) ProtoMessage()               {}
+func (*SyslogHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *SyslogHeader) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *SyslogHeader) GetFacility() uint32 {
+	if m != nil {
+		return m.Facility
+	}
+	return 0
+}
+
+func (m *SyslogHeader) GetSeverity() uint32 {
+	if m != nil {
+		return m.Severity
+	}
+	return 0
+}
+
+func (m *SyslogHeader) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *SyslogHeader) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *SyslogHeader) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *SyslogHeader) GetProcID() string {
+	if m != nil {
+		return m.ProcID
+	}
+	return ""
+}
+
+func (m *SyslogHeader) GetMsgID() string {
+	if m != nil {
+		return m.MsgID
+	}
+	return ""
+}
+
+// SuggestRequest - suggested templates request parameters.
+type SuggestRequest struct {
+	MinCount uint64 `protobuf:"varint,1,opt,name=MinCount,json=minCount" json:"MinCount,omitempty"`
+	Limit    uint32 `protobuf:"varint,2,opt,name=Limit,json=limit" json:"Limit,omitempty"`
+}
+
+func (m *SuggestRequest) Reset()                    { *m = SuggestRequest{} }
+func (m *SuggestRequest) String() string            { return proto.CompactTextString(m) }
+func (*SuggestRequest) ProtoMessage()               {}
+func (*SuggestRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *SuggestRequest) GetMinCount() uint64 {
+	if m != nil {
+		return m.MinCount
+	}
+	return 0
+}
+
+func (m *SuggestRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// SuggestResponse - unmatched message clusters.
+type SuggestResponse struct {
+	Suggestions []*Suggestion `protobuf:"bytes,1,rep,name=Suggestions,json=suggestions" json:"Suggestions,omitempty"`
+}
+
+func (m *SuggestResponse) Reset()                    { *m = SuggestResponse{} }
+func (m *SuggestResponse) String() string            { return proto.CompactTextString(m) }
+func (*SuggestResponse) ProtoMessage()               {}
+func (*SuggestResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *SuggestResponse) GetSuggestions() []*Suggestion {
+	if m != nil {
+		return m.Suggestions
+	}
+	return nil
+}
+
+// Suggestion - unmatched message cluster with suggested template.
+type Suggestion struct {
+	Template string   `protobuf:"bytes,1,opt,name=Template,json=template" json:"Template,omitempty"`
+	Pattern  string   `protobuf:"bytes,2,opt,name=Pattern,json=pattern" json:"Pattern,omitempty"`
+	Count    uint64   `protobuf:"varint,3,opt,name=Count,json=count" json:"Count,omitempty"`
+	Examples []string `protobuf:"bytes,4,rep,name=Examples,json=examples" json:"Examples,omitempty"`
+}
+
+func (m *Suggestion) Reset()                    { *m = Suggestion{} }
+func (m *Suggestion) String() string            { return proto.CompactTextString(m) }
+func (*Suggestion) ProtoMessage()               {}
+func (*Suggestion) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *Suggestion) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *Suggestion) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *Suggestion) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Suggestion) GetExamples() []string {
+	if m != nil {
+		return m.Examples
+	}
+	return nil
+}
+
+// DeadLetterRequest - dead letter subscription parameters.
+type DeadLetterRequest struct {
+	ClientName string             `protobuf:"bytes,1,opt,name=ClientName,json=clientName" json:"ClientName,omitempty"`
+	Reasons    []DeadLetterReason `protobuf:"varint,2,rep,packed,name=Reasons,json=reasons,enum=catcher.DeadLetterReason" json:"Reasons,omitempty"`
+	Templates  []string           `protobuf:"bytes,3,rep,name=Templates,json=templates" json:"Templates,omitempty"`
+	Nets       []string           `protobuf:"bytes,4,rep,name=Nets,json=nets" json:"Nets,omitempty"`
+}
+
+func (m *DeadLetterRequest) Reset()                    { *m = DeadLetterRequest{} }
+func (m *DeadLetterRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeadLetterRequest) ProtoMessage()               {}
+func (*DeadLetterRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *DeadLetterRequest) GetClientName() string {
+	if m != nil {
+		return m.ClientName
+	}
+	return ""
+}
+
+func (m *DeadLetterRequest) GetReasons() []DeadLetterReason {
+	if m != nil {
+		return m.Reasons
+	}
+	return nil
+}
+
+func (m *DeadLetterRequest) GetTemplates() []string {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+func (m *DeadLetterRequest) GetNets() []string {
+	if m != nil {
+		return m.Nets
+	}
+	return nil
+}
+
+// DeadLetter - message failed to parse.
+type DeadLetter struct {
+	Timestamp int64            `protobuf:"varint,1,opt,name=Timestamp,json=timestamp" json:"Timestamp,omitempty"`
+	Source    string           `protobuf:"bytes,2,opt,name=Source,json=source" json:"Source,omitempty"`
+	Reason    DeadLetterReason `protobuf:"varint,3,opt,name=Reason,json=reason,enum=catcher.DeadLetterReason" json:"Reason,omitempty"`
+	Template  string           `protobuf:"bytes,4,opt,name=Template,json=template" json:"Template,omitempty"`
+	Error     string           `protobuf:"bytes,5,opt,name=Error,json=error" json:"Error,omitempty"`
+	Text      string           `protobuf:"bytes,6,opt,name=Text,json=text" json:"Text,omitempty"`
+}
+
+func (m *DeadLetter) Reset()                    { *m = DeadLetter{} }
+func (m *DeadLetter) String() string            { return proto.CompactTextString(m) }
+func (*DeadLetter) ProtoMessage()               {}
+func (*DeadLetter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *DeadLetter) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *DeadLetter) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetReason() DeadLetterReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DeadLetterReason_Unmatched
+}
+
+func (m *DeadLetter) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+// TemplateStatsRequest - template statistics request parameters.
+type TemplateStatsRequest struct {
+	Unused  bool `protobuf:"varint,1,opt,name=Unused,json=unused" json:"Unused,omitempty"`
+	Failing bool `protobuf:"varint,2,opt,name=Failing,json=failing" json:"Failing,omitempty"`
+}
+
+func (m *TemplateStatsRequest) Reset()                    { *m = TemplateStatsRequest{} }
+func (m *TemplateStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*TemplateStatsRequest) ProtoMessage()               {}
+func (*TemplateStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *TemplateStatsRequest) GetUnused() bool {
+	if m != nil {
+		return m.Unused
+	}
+	return false
+}
+
+func (m *TemplateStatsRequest) GetFailing() bool {
+	if m != nil {
+		return m.Failing
+	}
+	return false
+}
+
+// TemplateStatsResponse - template usage statistics.
+type TemplateStatsResponse struct {
+	Templates []*TemplateStats `protobuf:"bytes,1,rep,name=Templates,json=templates" json:"Templates,omitempty"`
+}
+
+func (m *TemplateStatsResponse) Reset()                    { *m = TemplateStatsResponse{} }
+func (m *TemplateStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*TemplateStatsResponse) ProtoMessage()               {}
+func (*TemplateStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *TemplateStatsResponse) GetTemplates() []*TemplateStats {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+// TemplateStats - single template usage statistics.
+type TemplateStats struct {
+	Parser    string `protobuf:"bytes,1,opt,name=Parser,json=parser" json:"Parser,omitempty"`
+	ID        string `protobuf:"bytes,2,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Template  string `protobuf:"bytes,3,opt,name=Template,json=template" json:"Template,omitempty"`
+	Hits      uint64 `protobuf:"varint,4,opt,name=Hits,json=hits" json:"Hits,omitempty"`
+	Misses    uint64 `protobuf:"varint,5,opt,name=Misses,json=misses" json:"Misses,omitempty"`
+	Errors    uint64 `protobuf:"varint,6,opt,name=Errors,json=errors" json:"Errors,omitempty"`
+	LastMatch int64  `protobuf:"varint,7,opt,name=LastMatch,json=lastMatch" json:"LastMatch,omitempty"`
+	Pack      string `protobuf:"bytes,8,opt,name=Pack,json=pack" json:"Pack,omitempty"`
+}
+
+func (m *TemplateStats) Reset()                    { *m = TemplateStats{} }
+func (m *TemplateStats) String() string            { return proto.CompactTextString(m) }
+func (*TemplateStats) ProtoMessage()               {}
+func (*TemplateStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *TemplateStats) GetParser() string {
+	if m != nil {
+		return m.Parser
+	}
+	return ""
+}
+
+func (m *TemplateStats) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *TemplateStats) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *TemplateStats) GetHits() uint64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
+func (m *TemplateStats) GetMisses() uint64 {
+	if m != nil {
+		return m.Misses
+	}
+	return 0
+}
+
+func (m *TemplateStats) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *TemplateStats) GetLastMatch() int64 {
+	if m != nil {
+		return m.LastMatch
+	}
+	return 0
+}
+
+func (m *TemplateStats) GetPack() string {
+	if m != nil {
+		return m.Pack
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*EventRequest)(nil), "catcher.EventRequest")
 	proto.RegisterType((*Event)(nil), "catcher.Event")
+	proto.RegisterType((*Device)(nil), "catcher.Device")
+	proto.RegisterType((*InterfaceID)(nil), "catcher.InterfaceID")
+	proto.RegisterType((*SyslogHeader)(nil), "catcher.SyslogHeader")
+	proto.RegisterType((*SuggestRequest)(nil), "catcher.SuggestRequest")
+	proto.RegisterType((*SuggestResponse)(nil), "catcher.SuggestResponse")
+	proto.RegisterType((*Suggestion)(nil), "catcher.Suggestion")
+	proto.RegisterType((*DeadLetterRequest)(nil), "catcher.DeadLetterRequest")
+	proto.RegisterType((*DeadLetter)(nil), "catcher.DeadLetter")
+	proto.RegisterType((*TemplateStatsRequest)(nil), "catcher.TemplateStatsRequest")
+	proto.RegisterType((*TemplateStatsResponse)(nil), "catcher.TemplateStatsResponse")
+	proto.RegisterType((*TemplateStats)(nil), "catcher.TemplateStats")
 	proto.RegisterEnum("catcher.EventType", EventType_name, EventType_value)
 	proto.RegisterEnum("catcher.PortSpeed", PortSpeed_name, PortSpeed_value)
 	proto.RegisterEnum("catcher.PortDuplex", PortDuplex_name, PortDuplex_value)
+	proto.RegisterEnum("catcher.DeadLetterReason", DeadLetterReason_name, DeadLetterReason_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -218,6 +869,12 @@ const _ = grpc.SupportPackageIsVersion4
 type SyslogCatcherClient interface {
 	// Events - subsribe to event stream of specified events.
 	Events(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (SyslogCatcher_EventsClient, error)
+	// Suggestions - get suggested templates for unmatched messages.
+	Suggestions(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
+	// DeadLetters - subscribe to stream of messages failed to parse.
+	DeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (SyslogCatcher_DeadLettersClient, error)
+	// Templates - get template usage statistics.
+	Templates(ctx context.Context, in *TemplateStatsRequest, opts ...grpc.CallOption) (*TemplateStatsResponse, error)
 }
 
 type syslogCatcherClient struct {
@@ -260,11 +917,67 @@ func (x *syslogCatcherEventsClient) Recv() (*Event, error) {
 	return m, nil
 }
 
+func (c *syslogCatcherClient) Suggestions(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	out := new(SuggestResponse)
+	err := grpc.Invoke(ctx, "/catcher.SyslogCatcher/Suggestions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syslogCatcherClient) DeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (SyslogCatcher_DeadLettersClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_SyslogCatcher_serviceDesc.Streams[1], c.cc, "/catcher.SyslogCatcher/DeadLetters", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &syslogCatcherDeadLettersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SyslogCatcher_DeadLettersClient interface {
+	Recv() (*DeadLetter, error)
+	grpc.ClientStream
+}
+
+type syslogCatcherDeadLettersClient struct {
+	grpc.ClientStream
+}
+
+func (x *syslogCatcherDeadLettersClient) Recv() (*DeadLetter, error) {
+	m := new(DeadLetter)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *syslogCatcherClient) Templates(ctx context.Context, in *TemplateStatsRequest, opts ...grpc.CallOption) (*TemplateStatsResponse, error) {
+	out := new(TemplateStatsResponse)
+	err := grpc.Invoke(ctx, "/catcher.SyslogCatcher/Templates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for SyslogCatcher service
 
 type SyslogCatcherServer interface {
 	// Events - subsribe to event stream of specified events.
 	Events(*EventRequest, SyslogCatcher_EventsServer) error
+	// Suggestions - get suggested templates for unmatched messages.
+	Suggestions(context.Context, *SuggestRequest) (*SuggestResponse, error)
+	// DeadLetters - subscribe to stream of messages failed to parse.
+	DeadLetters(*DeadLetterRequest, SyslogCatcher_DeadLettersServer) error
+	// Templates - get template usage statistics.
+	Templates(context.Context, *TemplateStatsRequest) (*TemplateStatsResponse, error)
 }
 
 func RegisterSyslogCatcherServer(s *grpc.Server, srv SyslogCatcherServer) {
@@ -292,16 +1005,87 @@ func (x *syslogCatcherEventsServer) Send(m *Event) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _SyslogCatcher_Suggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyslogCatcherServer).Suggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/catcher.SyslogCatcher/Suggestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyslogCatcherServer).Suggestions(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyslogCatcher_DeadLetters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeadLetterRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyslogCatcherServer).DeadLetters(m, &syslogCatcherDeadLettersServer{stream})
+}
+
+type SyslogCatcher_DeadLettersServer interface {
+	Send(*DeadLetter) error
+	grpc.ServerStream
+}
+
+type syslogCatcherDeadLettersServer struct {
+	grpc.ServerStream
+}
+
+func (x *syslogCatcherDeadLettersServer) Send(m *DeadLetter) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SyslogCatcher_Templates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyslogCatcherServer).Templates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/catcher.SyslogCatcher/Templates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyslogCatcherServer).Templates(ctx, req.(*TemplateStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SyslogCatcher_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "catcher.SyslogCatcher",
 	HandlerType: (*SyslogCatcherServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Suggestions",
+			Handler:    _SyslogCatcher_Suggestions_Handler,
+		},
+		{
+			MethodName: "Templates",
+			Handler:    _SyslogCatcher_Templates_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Events",
 			Handler:       _SyslogCatcher_Events_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DeadLetters",
+			Handler:       _SyslogCatcher_DeadLetters_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "catcher.proto",
 }
@@ -309,29 +1093,89 @@ var _SyslogCatcher_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("catcher.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 372 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0xe1, 0x8e, 0x93, 0x40,
-	0x14, 0x85, 0x3b, 0x30, 0xb0, 0xcb, 0xb5, 0x90, 0xf1, 0x1a, 0x13, 0xe2, 0x0f, 0x43, 0xf6, 0x87,
-	0x21, 0x98, 0x6c, 0xba, 0xbb, 0x6f, 0x60, 0x6b, 0x6d, 0x8c, 0x6d, 0x0c, 0xb5, 0x0f, 0x50, 0xe8,
-	0xd5, 0x1a, 0x91, 0x19, 0x99, 0xa9, 0xda, 0x97, 0xf2, 0x19, 0xcd, 0x0c, 0xb4, 0xa6, 0x66, 0x7f,
-	0xcd, 0x9c, 0x73, 0x3f, 0x0e, 0x07, 0x66, 0x20, 0xae, 0xb7, 0xa6, 0xde, 0x53, 0x77, 0xab, 0x3a,
-	0x69, 0x24, 0x5e, 0x0d, 0xf2, 0xa6, 0x85, 0xf1, 0xdb, 0x9f, 0xd4, 0x9a, 0x92, 0x7e, 0x1c, 0x48,
-	0x1b, 0x7c, 0x09, 0x30, 0x6d, 0xbe, 0x52, 0x6b, 0x56, 0xdb, 0xef, 0x94, 0xb2, 0x8c, 0xe5, 0x51,
-	0x09, 0xf5, 0xd9, 0xc1, 0x02, 0x42, 0xc7, 0xeb, 0xd4, 0xcb, 0xfc, 0x3c, 0xb9, 0xc7, 0xdb, 0x53,
-	0xb0, 0xb3, 0x3f, 0x1d, 0x15, 0x95, 0x21, 0x39, 0x02, 0x11, 0xf8, 0x8a, 0x8c, 0x4e, 0xfd, 0xcc,
-	0xcf, 0xa3, 0x92, 0xb7, 0x64, 0xf4, 0xcd, 0x1f, 0x06, 0x81, 0x23, 0xf1, 0x15, 0x70, 0x4b, 0xbb,
-	0x77, 0x3c, 0x9e, 0xc3, 0xcd, 0x51, 0x91, 0x4d, 0x59, 0x48, 0x6d, 0x52, 0xcf, 0x75, 0xe1, 0x7b,
-	0xa9, 0x8d, 0xf5, 0x3e, 0xca, 0xce, 0xa4, 0x7e, 0xc6, 0xf2, 0xb8, 0xe4, 0x4a, 0x76, 0x06, 0x73,
-	0x08, 0xd6, 0x8a, 0x68, 0x97, 0xf2, 0xff, 0x02, 0x2d, 0xe9, 0x26, 0x65, 0xa0, 0xed, 0x82, 0xaf,
-	0x21, 0x9c, 0x1d, 0x54, 0x43, 0xbf, 0xd3, 0xc0, 0xa1, 0xcf, 0x2e, 0xd0, 0x7e, 0x54, 0x86, 0x3b,
-	0xb7, 0x16, 0x73, 0x88, 0xce, 0x8d, 0xf0, 0x09, 0x5c, 0x6d, 0xda, 0x6f, 0xad, 0xfc, 0xd5, 0x8a,
-	0x11, 0x02, 0x84, 0x96, 0xdf, 0x28, 0xc1, 0x70, 0x0c, 0xd7, 0xee, 0x59, 0x3b, 0xf1, 0x10, 0x21,
-	0xb1, 0xea, 0x83, 0x94, 0x6a, 0x46, 0x86, 0x6a, 0x23, 0xfc, 0xe2, 0x3d, 0x44, 0xe7, 0x22, 0x28,
-	0x60, 0x3c, 0xe4, 0x38, 0x2d, 0x46, 0x98, 0x00, 0xb8, 0xed, 0xdd, 0x64, 0xb2, 0xac, 0x04, 0xc3,
-	0x18, 0xa2, 0x41, 0x2f, 0x2b, 0xe1, 0xd9, 0xfc, 0x5e, 0xbe, 0xab, 0x84, 0x5f, 0x3c, 0x00, 0xfc,
-	0x6b, 0x8a, 0x4f, 0x21, 0x1e, 0xc2, 0x7a, 0x43, 0x8c, 0xf0, 0x1a, 0xf8, 0xfc, 0xd0, 0x34, 0x82,
-	0xd9, 0xdd, 0x62, 0xdb, 0x7c, 0x16, 0xde, 0xfd, 0x1b, 0x88, 0xd7, 0x47, 0xdd, 0xc8, 0x2f, 0xd3,
-	0xfe, 0x63, 0xf1, 0xee, 0x74, 0x94, 0xf8, 0xfc, 0xf2, 0xe7, 0x0f, 0x77, 0xe1, 0x45, 0x72, 0x69,
-	0x4f, 0x58, 0x15, 0xba, 0xdb, 0xf3, 0xf0, 0x37, 0x00, 0x00, 0xff, 0xff, 0x6a, 0xa6, 0xc0, 0xbc,
-	0x4e, 0x02, 0x00, 0x00,
+	// 1340 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x56, 0xc1, 0x6e, 0xdb, 0x46,
+	0x13, 0x0e, 0x25, 0x72, 0x45, 0x8e, 0x2c, 0x9b, 0xd9, 0x38, 0xfe, 0xf9, 0x1b, 0x6d, 0x60, 0xe8,
+	0xd0, 0x0a, 0x2e, 0x1a, 0x38, 0x4a, 0x5c, 0x14, 0x05, 0x5a, 0x24, 0x8d, 0x1c, 0xdb, 0x85, 0x15,
+	0x18, 0x54, 0xdd, 0x4b, 0x4f, 0x94, 0xb4, 0xb6, 0x09, 0x53, 0x5c, 0x96, 0xbb, 0x72, 0xed, 0xde,
+	0xfa, 0x0a, 0x3d, 0xf6, 0x49, 0x0a, 0xf4, 0x09, 0xfa, 0x1e, 0x79, 0x90, 0x62, 0x86, 0x4b, 0x52,
+	0x54, 0x93, 0xa2, 0x27, 0xf2, 0x9b, 0x19, 0xce, 0xce, 0x7c, 0xfb, 0xcd, 0x2e, 0xa1, 0x37, 0x8b,
+	0xf4, 0xec, 0x5a, 0xe4, 0x4f, 0xb3, 0x5c, 0x6a, 0xc9, 0x3b, 0x06, 0xf6, 0xff, 0xb0, 0x60, 0xe3,
+	0xe8, 0x56, 0xa4, 0x3a, 0x14, 0x3f, 0x2d, 0x85, 0xd2, 0xfc, 0x09, 0xc0, 0xeb, 0x24, 0x16, 0xa9,
+	0x7e, 0x1b, 0x2d, 0x44, 0x60, 0xed, 0x59, 0x03, 0x2f, 0x84, 0x59, 0x65, 0xe1, 0xfb, 0xc0, 0x28,
+	0x5e, 0x05, 0xad, 0xbd, 0xf6, 0x60, 0x73, 0xc8, 0x9f, 0x96, 0x99, 0xc9, 0xfc, 0xfd, 0x7d, 0x26,
+	0x42, 0x26, 0x28, 0x82, 0x73, 0xb0, 0xdf, 0x0a, 0xad, 0x82, 0xf6, 0x5e, 0x7b, 0xe0, 0x85, 0x76,
+	0x2a, 0xb4, 0xe2, 0x1f, 0x81, 0x87, 0x31, 0x98, 0x4b, 0x05, 0x36, 0x39, 0x3c, 0x5d, 0x1a, 0xf8,
+	0x36, 0x38, 0x93, 0x58, 0x0b, 0x15, 0x38, 0xe4, 0x71, 0x54, 0xac, 0x0b, 0x6b, 0x28, 0x13, 0xa1,
+	0x02, 0x56, 0x58, 0x73, 0x04, 0xfd, 0x5f, 0x1d, 0x70, 0x68, 0x4d, 0xfe, 0x09, 0xd8, 0x98, 0x93,
+	0xaa, 0x7d, 0x7f, 0x45, 0x36, 0x2e, 0x81, 0xf5, 0x9c, 0x48, 0xa5, 0x83, 0x16, 0x75, 0x65, 0x5f,
+	0x4b, 0xa5, 0xd1, 0x76, 0x2e, 0x73, 0x1d, 0xb4, 0xf7, 0xac, 0x41, 0x2f, 0xb4, 0x33, 0x99, 0x6b,
+	0x3e, 0x00, 0x67, 0x92, 0x09, 0x31, 0x0f, 0xec, 0xb5, 0x84, 0x18, 0x49, 0x9e, 0xd0, 0x51, 0xf8,
+	0xe0, 0x9f, 0x01, 0x1b, 0x2d, 0xb3, 0x44, 0xdc, 0x05, 0x0e, 0x85, 0x3e, 0x6a, 0x84, 0x16, 0xae,
+	0x90, 0xcd, 0xe9, 0xc9, 0x3f, 0x07, 0x76, 0x22, 0xa2, 0xb9, 0xc8, 0x03, 0xb6, 0x67, 0x0d, 0xba,
+	0xc3, 0xc7, 0x55, 0xf0, 0xe4, 0x5e, 0x25, 0xf2, 0xaa, 0x70, 0x86, 0xec, 0x9a, 0x9e, 0x54, 0x99,
+	0x10, 0x79, 0xd0, 0x29, 0xaa, 0xcd, 0x84, 0xc8, 0x71, 0x77, 0x26, 0xcb, 0x2c, 0xcb, 0x85, 0x52,
+	0x62, 0x1e, 0xb8, 0x7b, 0xd6, 0xc0, 0x0e, 0x41, 0x55, 0x16, 0xbe, 0x0b, 0x6e, 0xc9, 0x6e, 0xe0,
+	0xd1, 0x77, 0x6e, 0x49, 0x2e, 0xfa, 0x26, 0xe2, 0x56, 0xe4, 0xb1, 0xbe, 0x0f, 0x80, 0xba, 0x75,
+	0x95, 0xc1, 0xfc, 0x1b, 0x80, 0x57, 0x5a, 0xe7, 0xf1, 0x74, 0x89, 0xe4, 0x77, 0xf7, 0xda, 0x83,
+	0xee, 0xf0, 0x49, 0x93, 0xc7, 0xa7, 0x75, 0xc0, 0x51, 0xaa, 0xf3, 0xfb, 0x10, 0xa2, 0xca, 0x80,
+	0xbb, 0x4a, 0xbc, 0x8c, 0xa7, 0x99, 0x0a, 0x36, 0xa8, 0x2c, 0x4f, 0x95, 0x06, 0xf4, 0x9e, 0xa6,
+	0x5a, 0xe4, 0x97, 0xd1, 0x4c, 0x04, 0x3d, 0x2a, 0xcb, 0x8b, 0x4b, 0x03, 0xff, 0x02, 0xba, 0x95,
+	0xf7, 0x74, 0x14, 0x6c, 0x12, 0x37, 0xdb, 0xd5, 0xe2, 0x2b, 0xbe, 0xb0, 0x1b, 0xd7, 0x80, 0x7a,
+	0x15, 0x8b, 0x2c, 0x89, 0xb4, 0x08, 0xb6, 0x4c, 0xaf, 0x06, 0xf3, 0x4f, 0x81, 0x8d, 0xc4, 0x6d,
+	0x3c, 0x13, 0x81, 0x4f, 0xe9, 0xb6, 0xaa, 0x74, 0x85, 0x39, 0x64, 0x73, 0x7a, 0xee, 0x7e, 0x0d,
+	0x5b, 0x6b, 0x7d, 0x71, 0x1f, 0xda, 0x37, 0xe2, 0xde, 0x48, 0x1f, 0x5f, 0x51, 0x7f, 0xb7, 0x51,
+	0xb2, 0x14, 0x46, 0x38, 0x05, 0xf8, 0xaa, 0xf5, 0xa5, 0xd5, 0xff, 0xa5, 0x5c, 0x07, 0xab, 0x41,
+	0x6d, 0xa5, 0xf5, 0xd4, 0xb8, 0xd7, 0x06, 0xe3, 0x4e, 0xa2, 0xaa, 0x4b, 0xdd, 0xa1, 0xa8, 0xf9,
+	0x0e, 0xb0, 0x1f, 0x44, 0x3a, 0x97, 0x39, 0x29, 0xcf, 0x0b, 0xd9, 0x2d, 0x21, 0x5c, 0x6b, 0x2c,
+	0xe7, 0x22, 0x21, 0xed, 0x79, 0xa1, 0xb3, 0x40, 0x80, 0x19, 0x70, 0x02, 0x48, 0x65, 0x5e, 0x68,
+	0xe3, 0x00, 0xf4, 0xa3, 0x06, 0x6f, 0x18, 0x52, 0x0d, 0x81, 0x57, 0x0b, 0x7e, 0x92, 0xc8, 0x42,
+	0xf0, 0xbd, 0xd0, 0x56, 0x89, 0xd4, 0xb8, 0xf0, 0x58, 0xce, 0x97, 0x89, 0x30, 0x92, 0x67, 0x0b,
+	0x42, 0xd5, 0x20, 0xd8, 0xf5, 0x20, 0xf4, 0xdf, 0x59, 0xb0, 0xb1, 0xaa, 0x4d, 0xec, 0xf2, 0x3c,
+	0x8f, 0x25, 0x69, 0xc8, 0x2a, 0x34, 0x94, 0x19, 0x8c, 0xbe, 0x37, 0xd1, 0x2c, 0x4e, 0xd0, 0x57,
+	0x2c, 0xe8, 0x5e, 0x1a, 0xdc, 0xd0, 0x5e, 0x7b, 0x4d, 0x7b, 0x78, 0x22, 0xc4, 0x0b, 0xa1, 0x74,
+	0xb4, 0xc8, 0x68, 0xf5, 0x76, 0xe8, 0xe9, 0xd2, 0xd0, 0xe0, 0xd5, 0x59, 0xe3, 0x35, 0x80, 0xce,
+	0xab, 0x2c, 0x23, 0xb1, 0x33, 0x72, 0x75, 0xa2, 0x02, 0x62, 0x93, 0xe7, 0xb9, 0x9c, 0x9d, 0x8e,
+	0xcc, 0xf4, 0xb0, 0x8c, 0x10, 0xb1, 0xab, 0xae, 0x4e, 0x47, 0x81, 0x6b, 0xd8, 0x45, 0xd0, 0xff,
+	0x16, 0x36, 0x27, 0xcb, 0xab, 0x2b, 0xa1, 0xaa, 0x53, 0x70, 0x17, 0xdc, 0x71, 0x9c, 0xbe, 0x96,
+	0xcb, 0x54, 0x53, 0x9f, 0x76, 0xe8, 0x2e, 0x0c, 0xc6, 0x1c, 0x67, 0xf1, 0x22, 0x2e, 0x59, 0x75,
+	0x12, 0x04, 0xfd, 0x13, 0xd8, 0xaa, 0x72, 0xa8, 0x4c, 0xa6, 0x4a, 0xf0, 0x43, 0xe8, 0x1a, 0x53,
+	0x2c, 0x53, 0x15, 0x58, 0x34, 0x55, 0xf5, 0x09, 0x51, 0xfb, 0xc2, 0xae, 0xaa, 0xe3, 0xfa, 0x1a,
+	0x67, 0xbc, 0x84, 0x0d, 0x95, 0x5b, 0x6b, 0x2a, 0x0f, 0xa0, 0x73, 0x1e, 0x69, 0x2d, 0xf2, 0xd4,
+	0x48, 0xab, 0x93, 0x15, 0x10, 0x6b, 0x2c, 0x8a, 0x6f, 0x53, 0xf1, 0xce, 0x8c, 0x2a, 0xdf, 0x05,
+	0xf7, 0xe8, 0x2e, 0x5a, 0x64, 0x49, 0x75, 0xf4, 0xba, 0xc2, 0xe0, 0xfe, 0xef, 0x16, 0x3c, 0x1c,
+	0x89, 0x68, 0x7e, 0x26, 0x30, 0xc3, 0x7f, 0xbd, 0x0d, 0x9e, 0x43, 0x27, 0x14, 0x91, 0x92, 0x69,
+	0x79, 0x1d, 0xfc, 0x7f, 0x65, 0xd0, 0xea, 0x64, 0x18, 0x11, 0x76, 0xf2, 0x22, 0x92, 0x36, 0xdc,
+	0xb4, 0x50, 0xde, 0x0d, 0x5e, 0xd9, 0x53, 0x7d, 0x69, 0xd8, 0xf5, 0xa5, 0xd1, 0xff, 0xd3, 0x02,
+	0xa8, 0xf3, 0x35, 0x15, 0x63, 0xad, 0x2b, 0x66, 0x07, 0xd8, 0x44, 0x2e, 0xf3, 0x59, 0x39, 0x6f,
+	0x4c, 0x11, 0xe2, 0xcf, 0x80, 0x15, 0x95, 0x10, 0x29, 0xff, 0x5a, 0x2a, 0x2b, 0x4a, 0x6d, 0x90,
+	0x6f, 0xaf, 0x91, 0xbf, 0x0d, 0xce, 0x51, 0x9e, 0xcb, 0xdc, 0xa8, 0xd2, 0x11, 0x08, 0x68, 0x0a,
+	0xc5, 0x9d, 0x36, 0x7a, 0xb4, 0xb5, 0xb8, 0x43, 0x69, 0x6c, 0x97, 0x59, 0x26, 0x3a, 0xd2, 0xaa,
+	0x24, 0x77, 0x07, 0xd8, 0x45, 0xba, 0xc4, 0x83, 0x1c, 0x7b, 0x70, 0x43, 0xb6, 0x24, 0x84, 0xdb,
+	0xfa, 0x26, 0x8a, 0x93, 0x38, 0xbd, 0xa2, 0x0e, 0xdc, 0xb0, 0x73, 0x59, 0xc0, 0xfe, 0x18, 0x1e,
+	0xaf, 0x65, 0x32, 0x52, 0x7b, 0xb1, 0x4a, 0x69, 0x21, 0xb4, 0x9d, 0xaa, 0xbd, 0xe6, 0x27, 0x35,
+	0xd5, 0xfd, 0xbf, 0x2c, 0xe8, 0x35, 0x9c, 0x34, 0x37, 0x51, 0xae, 0x44, 0x6e, 0xf6, 0x9a, 0x65,
+	0x84, 0xf8, 0x26, 0xb4, 0x4e, 0x47, 0x86, 0xcf, 0x56, 0xdc, 0x3c, 0x7b, 0xdb, 0x6b, 0xc4, 0xe0,
+	0x2d, 0x1b, 0xd3, 0x06, 0xa2, 0xf4, 0xec, 0xeb, 0xb8, 0xc8, 0x3b, 0x8e, 0x95, 0xa2, 0x8b, 0x1d,
+	0xad, 0x6c, 0x41, 0x08, 0xed, 0x44, 0xa2, 0x22, 0xc2, 0xec, 0x90, 0x11, 0x8b, 0x24, 0x91, 0xb3,
+	0x48, 0xe9, 0x31, 0x76, 0x40, 0x23, 0xdc, 0x0e, 0xbd, 0xa4, 0x34, 0xd0, 0x51, 0x15, 0xcd, 0x6e,
+	0xcc, 0x10, 0xdb, 0x59, 0x34, 0xbb, 0xd9, 0xff, 0x11, 0xbc, 0xea, 0xba, 0xe7, 0x5d, 0xe8, 0x5c,
+	0xa4, 0x37, 0xa9, 0xfc, 0x39, 0xf5, 0x1f, 0x70, 0x00, 0x86, 0x07, 0xdb, 0x45, 0xe6, 0x5b, 0x7c,
+	0x03, 0x5c, 0x7c, 0x1f, 0xa1, 0xa7, 0xc5, 0x39, 0x6c, 0x22, 0x3a, 0x93, 0x32, 0x1b, 0x09, 0x2d,
+	0x66, 0xda, 0x6f, 0xf3, 0x47, 0xb0, 0x35, 0xd1, 0x32, 0x5f, 0xd4, 0xd7, 0xac, 0x6f, 0xef, 0xbf,
+	0xb3, 0xc0, 0xab, 0xee, 0x7e, 0xee, 0xc3, 0x86, 0xc9, 0x4e, 0xd8, 0x7f, 0xc0, 0x37, 0x01, 0xe8,
+	0xf5, 0xd9, 0xc1, 0xc1, 0x78, 0xea, 0x5b, 0xbc, 0x67, 0xae, 0xc3, 0x67, 0x08, 0x5b, 0xb8, 0x6a,
+	0x01, 0x8f, 0xa7, 0x7e, 0x9b, 0x6f, 0x41, 0x97, 0xd0, 0xf0, 0x90, 0xa2, 0xed, 0xca, 0x7d, 0x78,
+	0x3c, 0xf5, 0x9d, 0x95, 0x6f, 0x8f, 0xa7, 0x3e, 0xab, 0xe0, 0x10, 0xbd, 0x9d, 0x0a, 0xbe, 0x40,
+	0xaf, 0x5b, 0xc1, 0x43, 0x84, 0xde, 0x6a, 0x1d, 0xc7, 0x53, 0x1f, 0x2a, 0x3c, 0x24, 0xdc, 0xad,
+	0xf0, 0x0b, 0xc2, 0x1b, 0xd5, 0xe7, 0xaf, 0x96, 0x5a, 0xfa, 0xbd, 0xfd, 0xe7, 0x00, 0xf5, 0x6f,
+	0x0b, 0x7f, 0x08, 0x3d, 0xd3, 0x66, 0x61, 0xf0, 0x1f, 0x70, 0x17, 0xec, 0x37, 0xcb, 0x24, 0xf1,
+	0x2d, 0x7c, 0x3b, 0x89, 0x92, 0x4b, 0xbf, 0xb5, 0x7f, 0x00, 0xfe, 0xfa, 0xfc, 0x60, 0xde, 0x8b,
+	0x74, 0x41, 0xf2, 0x43, 0x7a, 0x7a, 0xe0, 0x8d, 0xa3, 0xe4, 0x52, 0xe6, 0x0b, 0x31, 0xf7, 0xad,
+	0xe1, 0x6f, 0x2d, 0xe8, 0x15, 0xb7, 0xca, 0xeb, 0x42, 0xa1, 0x38, 0x9a, 0xc5, 0x4f, 0x25, 0x7f,
+	0xdc, 0xfc, 0xe9, 0x30, 0xa3, 0xb2, 0xbb, 0xd9, 0x34, 0x1f, 0x58, 0xfc, 0x65, 0xe3, 0x70, 0xe5,
+	0xff, 0x5b, 0x3f, 0x56, 0xcb, 0x2f, 0x83, 0x7f, 0x3a, 0xcc, 0xcc, 0xbc, 0x84, 0x6e, 0x5d, 0xb8,
+	0xe2, 0xbb, 0xef, 0x3d, 0x0e, 0x8a, 0x24, 0x8f, 0xde, 0xe3, 0x3b, 0xb0, 0xf8, 0x77, 0x2b, 0x53,
+	0xc7, 0x3f, 0xfe, 0xc0, 0xbc, 0x99, 0x14, 0x4f, 0x3e, 0xe4, 0x2e, 0xaa, 0x99, 0x32, 0xfa, 0x31,
+	0x7f, 0xfe, 0xf7, 0x00, 0x55, 0x1d, 0x7d, 0x8c, 0xa9, 0x0b, 0x00, 0x00,
 }
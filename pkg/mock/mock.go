@@ -0,0 +1,124 @@
+// Package mock - генерация тестовых сообщений syslog (для cmd/mock и тестов производительности).
+package mock
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Patterns - шаблоны, которым соответствуют сообщения, формируемые Generator.Message.
+var Patterns = []string{
+	"link_up ~ $device_addr$ - - - port $device_port$ change link state to up with $port_speed$ $port_duplex$",
+	"link_down ~ $device_addr$ - - - port $device_port$ change link state to down",
+	"loopdetect ~ $device_addr$ - - - port $device_port$ disabled by loop detect service",
+	"link_up ~ $device_addr$ info: interface $device_port$ UP $port_speed$ $port_duplex$",
+	"link_down ~ $device_addr$ info: interface $device_port$ DOWN",
+	"loopdetect ~ $device_addr$ warn: loop detected on inteface $device_port$",
+}
+
+var (
+	// vendorFacilities - категории сообщений синтетических шаблонов.
+	vendorFacilities = []string{"LINK", "LINEPROTO", "PORT", "STP", "LOOP", "IFMGR", "ETHPORT", "LLDP"}
+	// vendorStates - варианты текста синтетических шаблонов.
+	vendorStates = []struct {
+		event string
+		text  string
+	}{
+		{"link_up", "changed state to up"},
+		{"link_down", "changed state to down"},
+		{"loopdetect", "blocked by loop detection"},
+		{"ignore", "neighbor information updated"},
+	}
+)
+
+// VendorPatterns - сформировать n синтетических шаблонов оборудования разных производителей
+// (вида "%LINK-3-UPDOWN17: Interface $device_port$ changed state to down").
+func VendorPatterns(n int) []string {
+	result := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		state := vendorStates[i%len(vendorStates)]
+		result = append(result, fmt.Sprintf("%s ~ $device_addr$ %s: Interface $device_port$ %s",
+			state.event, vendorTag(i), state.text))
+	}
+	return result
+}
+
+// vendorTag - уникальная метка синтетического шаблона.
+func vendorTag(i int) string {
+	return fmt.Sprintf("%%%s-%d-UPDOWN%d", vendorFacilities[i%len(vendorFacilities)], i%8, i)
+}
+
+// Generator - генератор случайных сообщений.
+type Generator struct {
+	rnd *rand.Rand
+}
+
+// NewGenerator - создать генератор с начальным значением seed
+// (одинаковое значение - одинаковая последовательность сообщений).
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Message - сгенерировать случайное сообщение по одному из шаблонов Patterns
+// или содержащее случайное количество полей "flood".
+func (g *Generator) Message() string {
+	switch g.rnd.Intn(10) {
+	case 0:
+		return fmt.Sprintf("%s - - - port %s change link state to up with %s", g.addr(), g.port(), g.speed())
+	case 1:
+		return fmt.Sprintf("%s - - - port %s change link state to down", g.addr(), g.port())
+	case 2:
+		return fmt.Sprintf("%s - - -  port %s disabled by loop detect service", g.addr(), g.port())
+	case 3:
+		return fmt.Sprintf("%s info: interface %s UP %s", g.addr(), g.port(), g.speed())
+	case 4:
+		return fmt.Sprintf("%s info: interface %s  DOWN", g.addr(), g.port())
+	case 5:
+		return fmt.Sprintf("%s warn: loop detected on inteface %s", g.addr(), g.port())
+	}
+	return fmt.Sprintf("%s - %s", g.addr(), strings.Repeat("flood ", g.rnd.Intn(15)+1))
+}
+
+// VendorMessage - сгенерировать случайное сообщение по одному из n шаблонов VendorPatterns.
+func (g *Generator) VendorMessage(n int) string {
+	i := g.rnd.Intn(n)
+	return fmt.Sprintf("%s %s: Interface %s %s", g.addr(), vendorTag(i), g.port(),
+		vendorStates[i%len(vendorStates)].text)
+}
+
+func (g *Generator) addr() string {
+	if g.rnd.Intn(4)%2 == 0 {
+		return fmt.Sprintf("192.168.0.%d", g.rnd.Intn(10)+1)
+	}
+
+	return fmt.Sprintf("10.0.0.%d", g.rnd.Intn(10)+1)
+}
+
+func (g *Generator) port() string {
+	if g.rnd.Intn(4)%2 == 0 {
+		return fmt.Sprintf("Ethernet1/0/%d", g.rnd.Intn(10)+1)
+	}
+
+	return fmt.Sprintf("%d", g.rnd.Intn(10))
+}
+
+func (g *Generator) speed() string {
+	var speed, duplex string
+	speeds := []string{"10", "100", "1000", "2.5G", "10G", "25Gb/s", "40G", "100000Mbps"}
+	speed = speeds[g.rnd.Intn(len(speeds))]
+	switch g.rnd.Intn(2) {
+	case 0:
+		duplex = "full"
+	case 1:
+		duplex = "half"
+	}
+	switch g.rnd.Intn(3) {
+	case 0:
+		return fmt.Sprintf("%sMB %s-duplex", speed, strings.ToUpper(duplex))
+	case 1:
+		return fmt.Sprintf("(speed:%s, duplex:%s)", speed, duplex)
+	}
+
+	return fmt.Sprintf("%s %s", speed, duplex)
+}
@@ -1,15 +1,19 @@
 package catcher
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
 	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
 	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/service/deadletter"
+	"github.com/neurovillain/syslog-catcher/pkg/service/inventory"
 	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
 	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
 	log "github.com/sirupsen/logrus"
@@ -22,6 +26,9 @@ type Service interface {
 	// получение и преобразование входящих данных и передача их подписчикам.
 	Serve()
 
+	// Reload - перечитать справочник устройств.
+	Reload() error
+
 	// Close - завершить работу и закрыть все соединения.
 	Close()
 }
@@ -40,25 +47,74 @@ func NewService(cfg *config.Config) (Service, error) {
 	}
 	log.SetOutput(io.MultiWriter(os.Stdout, f))
 
-	parser, err := parser.NewParser(cfg.Syslog.Templates)
+	var learner *parser.Learner
+	if !cfg.Syslog.Learn.Disabled {
+		learner, err = parser.NewLearner(parser.LearnOptions{
+			MaxClusters: cfg.Syslog.Learn.MaxClusters,
+			Similarity:  cfg.Syslog.Learn.Similarity,
+			Depth:       cfg.Syslog.Learn.Depth,
+			Examples:    cfg.Syslog.Learn.Examples,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("init learner err - %v", err)
+		}
+	}
+
+	letters, err := deadletter.New(deadletter.Options{
+		File:      cfg.Syslog.DeadLetter.File,
+		MaxSize:   cfg.Syslog.DeadLetter.MaxSize,
+		MaxFiles:  cfg.Syslog.DeadLetter.MaxFiles,
+		QueueSize: cfg.Syslog.DeadLetter.QueueSize,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("init parser err - %v", err)
+		return nil, fmt.Errorf("init dead letter store err - %v", err)
 	}
-	lsn, err := syslog.NewListener(cfg.Syslog.Listen, cfg.Syslog.BufSize, parser)
+
+	listeners, parsers, err := newListeners(cfg, func(text, source string, err error) {
+		if _, ok := err.(*parser.ErrUnknownFormat); ok && learner != nil {
+			learner.Add(text)
+		}
+		letters.Write(deadletter.NewRecord(text, source, err))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("init syslog listener err - %v", err)
+		letters.Close()
+		return nil, err
 	}
 
 	conn, err := net.Listen("tcp", cfg.GRPC.Listen)
 	if err != nil {
+		for _, lsn := range listeners {
+			lsn.Close()
+		}
+		letters.Close()
 		return nil, fmt.Errorf("init grpc conn err - %v", err)
 	}
 	log.Debugf("listen grpc requests on %s", cfg.GRPC.Listen)
 
+	var inv *inventory.Inventory
+	if len(cfg.Inventory.File) != 0 {
+		inv, err = inventory.New(inventory.Options{
+			File:   cfg.Inventory.File,
+			Reload: cfg.Inventory.Reload,
+		})
+		if err != nil {
+			for _, lsn := range listeners {
+				lsn.Close()
+			}
+			letters.Close()
+			conn.Close()
+			return nil, fmt.Errorf("init device inventory err - %v", err)
+		}
+	}
+
 	s := &service{
 		server:      grpc.NewServer(),
 		conn:        conn,
-		listener:    lsn,
+		listeners:   listeners,
+		parsers:     parsers,
+		learner:     learner,
+		letters:     letters,
+		inventory:   inv,
 		subsMu:      sync.Mutex{},
 		subscribers: make(map[string]*subscriber),
 		closed:      make(chan struct{}),
@@ -66,17 +122,214 @@ func NewService(cfg *config.Config) (Service, error) {
 
 	pb.RegisterSyslogCatcherServer(s.server, s)
 
+	if len(cfg.Metrics.Listen) != 0 {
+		s.metricsServer, s.metricsConn, err = newMetricsServer(cfg.Metrics.Listen, s)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
 	return s, nil
 }
 
+// newListeners - создать обработчики входящих сообщений, описанные в конфигурации.
+// Обработчики без собственного набора шаблонов используют общий набор
+// (общий обработчик шаблонов создается для каждого вида обработчика и набора правил имен интерфейсов).
+// Сообщения, которые не удалось преобразовать в события, передаются в failed.
+// Возвращает также созданные обработчики шаблонов (для статистики использования шаблонов).
+func newListeners(cfg *config.Config, failed syslog.FailFunc) ([]syslog.Listener, []namedParser, error) {
+	loc, err := cfg.Location()
+	if err != nil {
+		return nil, nil, fmt.Errorf("init syslog timezone err - %v", err)
+	}
+	common := make(map[string]parser.Parser)
+	parsers := make([]namedParser, 0)
+	result := make([]syslog.Listener, 0, len(cfg.Syslog.Listeners))
+	for k, v := range cfg.Syslog.Listeners {
+		key := v.Parser + "/" + v.Vendor
+		p, exist := common[key]
+		if len(v.Templates) != 0 || !exist {
+			p, err = NewListenerParser(cfg, k)
+		}
+		if err == nil && len(v.Templates) != 0 {
+			parsers = append(parsers, namedParser{name: fmt.Sprintf("listener #%d", k+1), parser: p})
+		} else if err == nil && !exist {
+			common[key] = p
+			parsers = append(parsers, namedParser{name: commonName(v), parser: p})
+		}
+		var lsn syslog.Listener
+		if err == nil {
+			lsn, err = newListener(v, loc, p, failed)
+		}
+		if err != nil {
+			for _, lsn := range result {
+				lsn.Close()
+			}
+			return nil, nil, fmt.Errorf("init syslog listener #%d err - %v", k+1, err)
+		}
+		result = append(result, lsn)
+	}
+
+	return result, parsers, nil
+}
+
+// NewListenerParser - создать обработчик шаблонов для обработчика входящих сообщений
+// с индексом k (с 0) так же, как при запуске сервиса: собственный набор шаблонов обработчика,
+// либо общий набор, с видом обработчика и правилами имен интерфейсов обработчика.
+// Для обработчиков текстовых шаблонов сообщение сначала сверяется с наборами шаблонов
+// оборудования (syslog.template_packs), в сети которых входит адрес отправителя.
+func NewListenerParser(cfg *config.Config, k int) (parser.Parser, error) {
+	if k < 0 || k >= len(cfg.Syslog.Listeners) {
+		return nil, fmt.Errorf("syslog listener #%d are not set", k+1)
+	}
+	opts, err := parserOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	v := cfg.Syslog.Listeners[k]
+	opts.Interfaces = cfg.Syslog.Interfaces[v.Vendor]
+	templates := v.Templates
+	if len(templates) == 0 {
+		templates = cfg.Syslog.Templates
+	}
+	kind := v.Parser
+	if len(kind) == 0 {
+		kind = parser.DefaultKind
+	}
+	if kind != parser.DefaultKind || len(cfg.Syslog.Packs) == 0 {
+		return parser.New(kind, templates, opts)
+	}
+
+	scopes := make([]parser.Scope, 0, len(cfg.Syslog.Packs))
+	for _, pack := range cfg.Syslog.Packs {
+		popts := opts
+		if len(pack.Interfaces) != 0 {
+			popts.Interfaces = cfg.Syslog.Interfaces[pack.Interfaces]
+		}
+		p, err := parser.New(kind, pack.Templates, popts)
+		if err != nil {
+			return nil, fmt.Errorf("template pack %s err - %v", pack.Name, err)
+		}
+		nets, err := pack.Networks()
+		if err != nil {
+			return nil, fmt.Errorf("template pack %s err - %v", pack.Name, err)
+		}
+		scopes = append(scopes, parser.Scope{Name: pack.Name, Nets: nets, Parser: p})
+	}
+	var fallback parser.Parser
+	if len(templates) != 0 {
+		if fallback, err = parser.New(kind, templates, opts); err != nil {
+			return nil, err
+		}
+	}
+	return parser.NewScoped(scopes, fallback), nil
+}
+
+// namedParser - обработчик шаблонов с именем для статистики использования шаблонов.
+type namedParser struct {
+	name   string
+	parser parser.Parser
+}
+
+// commonName - имя общего обработчика шаблонов (с видом обработчика и набором правил имен интерфейсов).
+func commonName(cfg config.Listener) string {
+	if len(cfg.Parser) == 0 && len(cfg.Vendor) == 0 {
+		return "common"
+	}
+	kind := cfg.Parser
+	if len(kind) == 0 {
+		kind = parser.DefaultKind
+	}
+	if len(cfg.Vendor) == 0 {
+		return "common " + kind
+	}
+	return fmt.Sprintf("common %s/%s", kind, cfg.Vendor)
+}
+
+// parserOptions - сформировать общие параметры обработчиков шаблонов.
+func parserOptions(cfg *config.Config) (parser.Options, error) {
+	result := parser.Options{
+		Patterns:   cfg.Syslog.Patterns,
+		Fields:     cfg.Syslog.Fields,
+		EventTypes: make([]parser.EventType, 0, len(cfg.Syslog.EventTypes)),
+	}
+	for _, v := range cfg.Syslog.EventTypes {
+		level, err := v.Level()
+		if err != nil {
+			return result, fmt.Errorf("init event types err - %v", err)
+		}
+		result.EventTypes = append(result.EventTypes, parser.EventType{
+			Name:        v.Name,
+			Description: v.Description,
+			Severity:    level,
+		})
+	}
+	return result, nil
+}
+
+// rateLimit - параметры ограничения интенсивности приема сообщений обработчика.
+func rateLimit(cfg config.RateLimit) syslog.RateLimit {
+	if cfg.Disabled {
+		return syslog.RateLimit{}
+	}
+	return syslog.RateLimit{
+		SourceRate:   cfg.SourceRate,
+		SourceBurst:  cfg.SourceBurst,
+		SubnetRate:   cfg.SubnetRate,
+		SubnetBurst:  cfg.SubnetBurst,
+		SubnetPrefix: cfg.SubnetPrefix,
+		Interval:     cfg.Interval,
+	}
+}
+
+// newListener - создать обработчик входящих сообщений для указанного протокола.
+func newListener(cfg config.Listener, loc *time.Location, parser parser.Parser, failed syslog.FailFunc) (syslog.Listener, error) {
+	opts := syslog.Options{
+		BufSize:      cfg.BufSize,
+		IdleTimeout:  cfg.IdleTimeout,
+		Location:     loc,
+		PreferSource: cfg.HostSource == config.HostSourcePacket,
+		Workers:      cfg.Workers,
+		QueueSize:    cfg.QueueSize,
+		Sockets:      cfg.Sockets,
+		Batch:        cfg.Batch,
+		ReadBuffer:   cfg.ReadBuffer,
+		RateLimit:    rateLimit(cfg.RateLimit),
+		Failed:       failed,
+	}
+	switch cfg.Protocol {
+	case "tcp":
+		return syslog.NewTCPListener(cfg.Listen, opts, parser)
+	case "tls":
+		tlsCfg, err := cfg.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("tls params err - %v", err)
+		}
+		return syslog.NewTLSListener(cfg.Listen, tlsCfg, opts, parser)
+	case "unix", "unixgram":
+		return syslog.NewUnixListener(cfg.Protocol, cfg.Listen, cfg.Mode, opts, parser)
+	}
+
+	return syslog.NewListener(cfg.Listen, opts, parser)
+}
+
 // service - реализация интерфейса Service.
 type service struct {
-	server      *grpc.Server
-	conn        net.Listener
-	listener    syslog.Listener
-	subsMu      sync.Mutex
-	subscribers map[string]*subscriber
-	closed      chan struct{}
+	server    *grpc.Server
+	conn      net.Listener
+	listeners []syslog.Listener
+	parsers   []namedParser
+	learner   *parser.Learner
+	letters   *deadletter.Store
+	// справочник устройств (nil - не используется)
+	inventory *inventory.Inventory
+	// HTTP-сервер метрик (nil - не используется)
+	metricsServer *http.Server
+	metricsConn   net.Listener
+	subsMu        sync.Mutex
+	subscribers   map[string]*subscriber
+	closed        chan struct{}
 }
 
 // Serve - запустить основной цикл работы сервиса -
@@ -84,9 +337,14 @@ type service struct {
 // передача их подписчикам.
 func (s *service) Serve() {
 	ch := make(chan *pb.Event)
-	go s.listener.Listen(ch)
+	for _, lsn := range s.listeners {
+		go lsn.Listen(ch)
+	}
 	go s.server.Serve(s.conn)
 	defer s.server.GracefulStop()
+	if s.metricsServer != nil {
+		go s.metricsServer.Serve(s.metricsConn)
+	}
 	log.Info("----- syslog catcher service is launched -----")
 	for {
 		select {
@@ -94,6 +352,9 @@ func (s *service) Serve() {
 			return
 		case msg := <-ch:
 			{
+				if s.inventory != nil {
+					s.inventory.Enrich(msg)
+				}
 				s.subsMu.Lock()
 				for _, c := range s.subscribers {
 					c.pull(msg)
@@ -106,7 +367,7 @@ func (s *service) Serve() {
 
 // Events - (реализация метода SyslogCatcherServer) - подключение нового подписчика к сервису.
 func (s *service) Events(rq *pb.EventRequest, stream pb.SyslogCatcher_EventsServer) error {
-	sub, err := newSubscriber(rq.GetClientName(), rq.GetEvents(), rq.GetNets())
+	sub, err := newSubscriber(rq.GetClientName(), rq.GetEvents(), rq.GetTypeNames(), rq.GetNets(), rq.GetSites(), rq.GetRoles())
 	if err != nil {
 		return err
 	}
@@ -139,10 +400,102 @@ func (s *service) Events(rq *pb.EventRequest, stream pb.SyslogCatcher_EventsServ
 	}
 }
 
+// Suggestions - (реализация метода SyslogCatcherServer) - предлагаемые шаблоны
+// для сообщений, не совпавших ни с одним шаблоном.
+func (s *service) Suggestions(ctx context.Context, rq *pb.SuggestRequest) (*pb.SuggestResponse, error) {
+	if s.learner == nil {
+		return nil, fmt.Errorf("learning of unmatched messages is disabled")
+	}
+	result := &pb.SuggestResponse{}
+	for _, v := range s.learner.Suggest(rq.GetMinCount(), int(rq.GetLimit())) {
+		result.Suggestions = append(result.Suggestions, &pb.Suggestion{
+			Template: v.Template,
+			Pattern:  v.Pattern,
+			Count:    v.Count,
+			Examples: v.Examples,
+		})
+	}
+	return result, nil
+}
+
+// DeadLetters - (реализация метода SyslogCatcherServer) - подключение подписчика
+// к потоку сообщений, которые не удалось преобразовать в события.
+func (s *service) DeadLetters(rq *pb.DeadLetterRequest, stream pb.SyslogCatcher_DeadLettersServer) error {
+	filter, err := newLetterFilter(rq)
+	if err != nil {
+		return err
+	}
+	ch := s.letters.Subscribe(1024)
+	log.Infof("client %s is connected to dead letters", rq.GetClientName())
+	defer func() {
+		s.letters.Unsubscribe(ch)
+		log.Infof("client %s is disconect from dead letters", rq.GetClientName())
+	}()
+
+	for {
+		select {
+		case <-s.closed:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case rec := <-ch:
+			if msg := filter.apply(rec); msg != nil {
+				if err := stream.Send(msg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Templates - (реализация метода SyslogCatcherServer) - статистика использования шаблонов.
+func (s *service) Templates(ctx context.Context, rq *pb.TemplateStatsRequest) (*pb.TemplateStatsResponse, error) {
+	result := &pb.TemplateStatsResponse{}
+	for _, p := range s.parsers {
+		for _, v := range p.parser.Stats() {
+			if (rq.GetUnused() && v.Hits != 0) || (rq.GetFailing() && v.Errors == 0) {
+				continue
+			}
+			stats := &pb.TemplateStats{
+				Parser:   p.name,
+				Pack:     v.Pack,
+				ID:       v.ID,
+				Template: v.Template,
+				Hits:     v.Hits,
+				Misses:   v.Misses,
+				Errors:   v.Errors,
+			}
+			if !v.LastMatch.IsZero() {
+				stats.LastMatch = v.LastMatch.UnixNano()
+			}
+			result.Templates = append(result.Templates, stats)
+		}
+	}
+	return result, nil
+}
+
+// Reload - перечитать справочник устройств (если используется).
+func (s *service) Reload() error {
+	if s.inventory == nil {
+		return nil
+	}
+	return s.inventory.Reload()
+}
+
 // Close - завершить работу и закрыть все соединения.
 func (s *service) Close() {
-	s.listener.Close()
+	for _, lsn := range s.listeners {
+		lsn.Close()
+	}
 	s.conn.Close()
+	if s.metricsServer != nil {
+		s.metricsServer.Close()
+		s.metricsConn.Close()
+	}
+	s.letters.Close()
+	if s.inventory != nil {
+		s.inventory.Close()
+	}
 	close(s.closed)
 	log.Info("----- syslog catcher service is stopped -----")
 }
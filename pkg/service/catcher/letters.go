@@ -0,0 +1,76 @@
+package catcher
+
+import (
+	"net"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/deadletter"
+)
+
+// letterReason - соответствие причин записей хранилища значениям перечисления GRPC.
+var letterReason = map[string]pb.DeadLetterReason{
+	deadletter.ReasonUnmatched: pb.DeadLetterReason_Unmatched,
+	deadletter.ReasonMalformed: pb.DeadLetterReason_Malformed,
+}
+
+// letterFilter - условия отбора сообщений, не преобразованных в события, для подписчика.
+type letterFilter struct {
+	reasons   map[pb.DeadLetterReason]struct{}
+	templates map[string]struct{}
+	nets      []*net.IPNet
+}
+
+// newLetterFilter - сформировать условия отбора по запросу подписчика.
+func newLetterFilter(rq *pb.DeadLetterRequest) (*letterFilter, error) {
+	f := &letterFilter{
+		reasons:   make(map[pb.DeadLetterReason]struct{}),
+		templates: make(map[string]struct{}),
+		nets:      make([]*net.IPNet, 0),
+	}
+	for _, r := range rq.GetReasons() {
+		f.reasons[r] = struct{}{}
+	}
+	for _, t := range rq.GetTemplates() {
+		f.templates[t] = struct{}{}
+	}
+	for _, n := range rq.GetNets() {
+		_, nwk, err := net.ParseCIDR(n)
+		if err != nil {
+			return nil, err
+		}
+		f.nets = append(f.nets, nwk)
+	}
+	return f, nil
+}
+
+// apply - преобразовать запись в формат GRPC, nil - если запись не соответствует условиям.
+func (f *letterFilter) apply(rec *deadletter.Record) *pb.DeadLetter {
+	reason := letterReason[rec.Reason]
+	if _, ok := f.reasons[reason]; len(f.reasons) != 0 && !ok {
+		return nil
+	}
+	if _, ok := f.templates[rec.Template]; len(f.templates) != 0 && !ok {
+		return nil
+	}
+	if len(f.nets) != 0 {
+		addr := net.ParseIP(rec.Source)
+		found := false
+		for _, nwk := range f.nets {
+			if addr != nil && nwk.Contains(addr) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return &pb.DeadLetter{
+		Timestamp: rec.Time.UnixNano(),
+		Source:    rec.Source,
+		Reason:    reason,
+		Template:  rec.Template,
+		Error:     rec.Error,
+		Text:      rec.Text,
+	}
+}
@@ -0,0 +1,62 @@
+package catcher
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// metricsPath - путь HTTP-запроса метрик сервиса (формат expvar).
+const metricsPath = "/debug/vars"
+
+// newMetricsServer - создать HTTP-сервер метрик сервиса.
+func newMetricsServer(addr string, s *service) (*http.Server, net.Listener, error) {
+	conn, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("init metrics conn err - %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, s.metrics)
+	log.Debugf("listen metrics requests on %s%s", addr, metricsPath)
+	return &http.Server{Handler: mux}, conn, nil
+}
+
+// metrics - вывести метрики сервиса в формате expvar: стандартные переменные
+// (cmdline, memstats), счетчики обработчиков входящих сообщений (syslog_listeners),
+// количество сообщений, отброшенных ограничением интенсивности, по отправителям (syslog_suppressed),
+// статистику использования шаблонов (syslog_templates) и количество записей хранилища сообщений,
+// не сохраненных в файл (syslog_dead_letters).
+func (s *service) metrics(w http.ResponseWriter, r *http.Request) {
+	vars := make(map[string]interface{})
+	expvar.Do(func(kv expvar.KeyValue) {
+		vars[kv.Key] = json.RawMessage(kv.Value.String())
+	})
+
+	listeners := make(map[string]interface{}, len(s.listeners))
+	suppressed := make(map[string]interface{})
+	for k, lsn := range s.listeners {
+		name := fmt.Sprintf("listener #%d", k+1)
+		listeners[name] = lsn.Counters()
+		if v := lsn.Suppressed(); v != nil {
+			suppressed[name] = v
+		}
+	}
+	vars["syslog_listeners"] = listeners
+	vars["syslog_suppressed"] = suppressed
+
+	templates := make(map[string]interface{}, len(s.parsers))
+	for _, p := range s.parsers {
+		templates[p.name] = p.parser.Stats()
+	}
+	vars["syslog_templates"] = templates
+	vars["syslog_dead_letters"] = map[string]uint64{"dropped": s.letters.Dropped()}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(vars); err != nil {
+		log.Debugf("write metrics err - %v", err)
+	}
+}
@@ -12,25 +12,36 @@ type subscriber struct {
 	name   string
 	stream chan *pb.Event
 	events map[pb.EventType]struct{}
+	names  map[string]struct{}
 	nets   []*net.IPNet
+	sites  map[string]struct{}
+	roles  map[string]struct{}
 }
 
 // newSubscriber - создать новый экземпляр подписчика на сообщения.
 // events -  requested event-types,
-// nets - networks for processing.
-func newSubscriber(name string, events []pb.EventType, nets []string) (*subscriber, error) {
-	if len(events) == 0 {
+// names - requested event-type names (configured types),
+// nets - networks for processing,
+// sites, roles - device sites and roles (from device inventory).
+func newSubscriber(name string, events []pb.EventType, names []string, nets []string, sites []string, roles []string) (*subscriber, error) {
+	if len(events) == 0 && len(names) == 0 {
 		return nil, fmt.Errorf("create subscriber - no events for service %s", name)
 	}
 	c := &subscriber{
 		name:   name,
 		stream: make(chan *pb.Event, 1024),
 		events: make(map[pb.EventType]struct{}),
+		names:  make(map[string]struct{}),
 		nets:   make([]*net.IPNet, 0),
+		sites:  make(map[string]struct{}),
+		roles:  make(map[string]struct{}),
 	}
 	for _, e := range events {
 		c.events[e] = struct{}{}
 	}
+	for _, n := range names {
+		c.names[n] = struct{}{}
+	}
 	for _, n := range nets {
 		_, nwk, err := net.ParseCIDR(n)
 		if err != nil {
@@ -38,15 +49,19 @@ func newSubscriber(name string, events []pb.EventType, nets []string) (*subscrib
 		}
 		c.nets = append(c.nets, nwk)
 	}
+	for _, v := range sites {
+		c.sites[v] = struct{}{}
+	}
+	for _, v := range roles {
+		c.roles[v] = struct{}{}
+	}
 	return c, nil
 }
 
 // pull - передать сообщение подписчику.
 func (c *subscriber) pull(msg *pb.Event) {
-	if len(c.events) != 0 {
-		if _, ok := c.events[msg.Type]; !ok {
-			return
-		}
+	if !c.accept(msg) {
+		return
 	}
 	if len(c.nets) != 0 {
 		found := false
@@ -64,6 +79,26 @@ func (c *subscriber) pull(msg *pb.Event) {
 			return
 		}
 	}
+	if _, ok := c.sites[msg.GetDevice().GetSite()]; len(c.sites) != 0 && !ok {
+		return
+	}
+	if _, ok := c.roles[msg.GetDevice().GetRole()]; len(c.roles) != 0 && !ok {
+		return
+	}
 
 	c.stream <- msg
 }
+
+// accept - проверить, что тип события запрошен подписчиком -
+// по значению перечисления EventType, либо по имени типа
+// (типы из конфигурации имеют значение перечисления Unknown).
+func (c *subscriber) accept(msg *pb.Event) bool {
+	if len(c.events) == 0 && len(c.names) == 0 {
+		return true
+	}
+	if _, ok := c.names[msg.TypeName]; ok {
+		return true
+	}
+	_, ok := c.events[msg.Type]
+	return ok
+}
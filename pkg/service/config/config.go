@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -14,13 +16,85 @@ type Config struct {
 		File  string `yaml:"file"`
 	} `yaml:"log"`
 	Syslog struct {
-		Listen    string   `yaml:"listen"`
-		Templates []string `yaml:"templates"`
-		BufSize   int      `yaml:"buf_size"`
+		Listeners   []Listener                   `yaml:"listeners"`
+		Listen      string                       `yaml:"listen"`
+		Protocol    string                       `yaml:"protocol"`
+		IdleTimeout time.Duration                `yaml:"idle_timeout"`
+		HostSource  string                       `yaml:"host_source"`
+		Workers     int                          `yaml:"workers"`
+		QueueSize   int                          `yaml:"queue_size"`
+		Templates   []string                     `yaml:"templates"`
+		PacksDir    string                       `yaml:"template_packs"`
+		Packs       []TemplatePack               `yaml:"-"`
+		Patterns    map[string]string            `yaml:"patterns"`
+		EventTypes  []EventType                  `yaml:"event_types"`
+		Fields      map[string]string            `yaml:"fields"`
+		Interfaces  map[string]map[string]string `yaml:"interfaces"`
+		Vendor      string                       `yaml:"vendor"`
+		Parser      string                       `yaml:"parser"`
+		BufSize     int                          `yaml:"buf_size"`
+		Timezone    string                       `yaml:"timezone"`
+		TLS         TLS                          `yaml:"tls"`
+		RateLimit   RateLimit                    `yaml:"rate_limit"`
+		Learn       Learn                        `yaml:"learn"`
+		DeadLetter  DeadLetter                   `yaml:"dead_letter"`
 	} `yaml:"syslog"`
 	GRPC struct {
 		Listen string `yaml:"listen"`
 	} `yaml:"grpc"`
+	Metrics struct {
+		Listen string `yaml:"listen"`
+	} `yaml:"metrics"`
+	Inventory Inventory `yaml:"inventory"`
+}
+
+// setDefaults - заполнить незаданные параметры обработчиков входящих сообщений.
+// Если список listeners не задан - используется единственный обработчик,
+// описанный параметрами listen, protocol и tls.
+func (c *Config) setDefaults() {
+	if len(c.Syslog.Listeners) == 0 && len(c.Syslog.Listen) != 0 {
+		c.Syslog.Listeners = append(c.Syslog.Listeners, Listener{
+			Protocol: c.Syslog.Protocol,
+			Listen:   c.Syslog.Listen,
+			TLS:      c.Syslog.TLS,
+		})
+	}
+	for k := range c.Syslog.Listeners {
+		l := &c.Syslog.Listeners[k]
+		if len(l.Protocol) == 0 {
+			l.Protocol = "udp"
+		}
+		if l.BufSize == 0 {
+			l.BufSize = c.Syslog.BufSize
+		}
+		if l.IdleTimeout == 0 {
+			l.IdleTimeout = c.Syslog.IdleTimeout
+		}
+		if len(l.HostSource) == 0 {
+			l.HostSource = c.Syslog.HostSource
+		}
+		if l.Workers == 0 {
+			l.Workers = c.Syslog.Workers
+		}
+		if l.QueueSize == 0 {
+			l.QueueSize = c.Syslog.QueueSize
+		}
+		if len(l.Vendor) == 0 {
+			l.Vendor = c.Syslog.Vendor
+		}
+		if len(l.Parser) == 0 {
+			l.Parser = c.Syslog.Parser
+		}
+		if l.RateLimit == (RateLimit{}) {
+			l.RateLimit = c.Syslog.RateLimit
+		}
+		if len(l.HostSource) == 0 {
+			l.HostSource = HostSourceMessage
+		}
+		if l.Mode == 0 && (l.Protocol == "unix" || l.Protocol == "unixgram") {
+			l.Mode = defaultSocketMode
+		}
+	}
 }
 
 // isValid - проверка корректности входящих данных.
@@ -31,11 +105,41 @@ func (c *Config) isValid() error {
 	if len(c.Log.File) == 0 {
 		return fmt.Errorf("log output file are not set")
 	}
-	if len(c.Syslog.Listen) == 0 {
+	if len(c.Syslog.Listeners) == 0 {
 		return fmt.Errorf("syslog listen port are not set")
 	}
-	if len(c.Syslog.Templates) == 0 {
-		return fmt.Errorf("no parsing templates are set")
+	for k, l := range c.Syslog.Listeners {
+		if err := l.isValid(); err != nil {
+			return fmt.Errorf("syslog listener #%d err - %v", k+1, err)
+		}
+		if _, exist := c.Syslog.Interfaces[l.Vendor]; len(l.Vendor) != 0 && !exist {
+			return fmt.Errorf("no interface rules are set for vendor %s of syslog listener #%d", l.Vendor, k+1)
+		}
+		if len(l.Templates) == 0 && len(c.Syslog.Templates) == 0 && len(c.Syslog.Packs) == 0 {
+			return fmt.Errorf("no parsing templates are set for syslog listener #%d", k+1)
+		}
+	}
+	for _, p := range c.Syslog.Packs {
+		if _, exist := c.Syslog.Interfaces[p.Interfaces]; len(p.Interfaces) != 0 && !exist {
+			return fmt.Errorf("no interface rules are set for vendor %s of template pack %s", p.Interfaces, p.Name)
+		}
+	}
+	for k, t := range c.Syslog.EventTypes {
+		if err := t.isValid(); err != nil {
+			return fmt.Errorf("syslog event type #%d err - %v", k+1, err)
+		}
+	}
+	if err := c.Syslog.Learn.isValid(); err != nil {
+		return err
+	}
+	if err := c.Syslog.DeadLetter.isValid(); err != nil {
+		return err
+	}
+	if _, err := c.Location(); err != nil {
+		return fmt.Errorf("syslog timezone are invalid - %v", err)
+	}
+	if err := c.Inventory.isValid(); err != nil {
+		return err
 	}
 	if len(c.GRPC.Listen) == 0 {
 		return fmt.Errorf("grpc listen port are not set")
@@ -44,6 +148,15 @@ func (c *Config) isValid() error {
 	return nil
 }
 
+// Location - часовой пояс устройств-отправителей сообщений.
+// Если часовой пояс не указан - используется локальный.
+func (c *Config) Location() (*time.Location, error) {
+	if len(c.Syslog.Timezone) == 0 {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.Syslog.Timezone)
+}
+
 // ParseFile - загрузить данные конфигурации из файла.
 func ParseFile(name string) (*Config, error) {
 	buf, err := ioutil.ReadFile(name)
@@ -57,6 +170,20 @@ func ParseFile(name string) (*Config, error) {
 		return nil, fmt.Errorf("parse cfg data err - %v", err)
 	}
 
+	if len(cfg.Syslog.PacksDir) != 0 {
+		dir := cfg.Syslog.PacksDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(name), dir)
+		}
+		if cfg.Syslog.Packs, err = LoadTemplatePacks(dir); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.Inventory.File) != 0 && !filepath.IsAbs(cfg.Inventory.File) {
+		cfg.Inventory.File = filepath.Join(filepath.Dir(name), cfg.Inventory.File)
+	}
+
+	cfg.setDefaults()
 	if err = cfg.isValid(); err != nil {
 		return nil, fmt.Errorf("check cfg err - %v", err)
 	}
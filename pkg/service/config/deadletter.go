@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+)
+
+// DeadLetter - параметры хранилища сообщений, которые не удалось преобразовать в события.
+type DeadLetter struct {
+	File      string `yaml:"file"`
+	MaxSize   int64  `yaml:"max_size"`
+	MaxFiles  int    `yaml:"max_files"`
+	QueueSize int    `yaml:"queue_size"`
+}
+
+// isValid - проверка корректности параметров хранилища.
+func (d *DeadLetter) isValid() error {
+	if d.MaxSize < 0 || d.MaxFiles < 0 || d.QueueSize < 0 {
+		return fmt.Errorf("dead letter max size, max files or queue size are invalid")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var (
+	// severityKeyword - имена уровней важности syslog (RFC 5424, 6.2.1).
+	severityKeyword = map[string]uint32{
+		"emergency": 0,
+		"alert":     1,
+		"critical":  2,
+		"error":     3,
+		"warning":   4,
+		"notice":    5,
+		"info":      6,
+		"debug":     7,
+	}
+)
+
+// EventType - тип события, дополняющий встроенные типы шаблонов.
+// Severity - имя уровня важности (emergency ... debug) или его номер (0 - 7).
+type EventType struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Severity    string `yaml:"severity"`
+}
+
+// isValid - проверка корректности параметров типа события.
+func (t *EventType) isValid() error {
+	if len(t.Name) == 0 {
+		return fmt.Errorf("event type name are not set")
+	}
+	if _, err := t.Level(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Level - числовое значение уровня важности (по умолчанию - notice).
+func (t *EventType) Level() (uint32, error) {
+	if len(t.Severity) == 0 {
+		return severityKeyword["notice"], nil
+	}
+	if level, exist := severityKeyword[t.Severity]; exist {
+		return level, nil
+	}
+	level, err := strconv.ParseUint(t.Severity, 10, 32)
+	if err != nil || level > uint64(severityKeyword["debug"]) {
+		return 0, fmt.Errorf("event type %s severity \"%s\" are unknown", t.Name, t.Severity)
+	}
+	return uint32(level), nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Inventory - параметры справочника устройств.
+// Относительный путь к файлу справочника отсчитывается от каталога файла конфигурации.
+type Inventory struct {
+	File   string        `yaml:"file"`
+	Reload time.Duration `yaml:"reload"`
+}
+
+// isValid - проверка корректности параметров справочника.
+func (i *Inventory) isValid() error {
+	if i.Reload < 0 {
+		return fmt.Errorf("inventory reload interval are invalid")
+	}
+	return nil
+}
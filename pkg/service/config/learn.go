@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Learn - параметры группировки сообщений, не совпавших ни с одним шаблоном,
+// для формирования предлагаемых шаблонов.
+type Learn struct {
+	Disabled    bool    `yaml:"disabled"`
+	MaxClusters int     `yaml:"max_clusters"`
+	Similarity  float64 `yaml:"similarity"`
+	Depth       int     `yaml:"depth"`
+	Examples    int     `yaml:"examples"`
+}
+
+// isValid - проверка корректности параметров группировки.
+func (l *Learn) isValid() error {
+	if l.MaxClusters < 0 || l.Depth < 0 || l.Examples < 0 {
+		return fmt.Errorf("learn max clusters, depth or examples are invalid")
+	}
+	if l.Similarity < 0 || l.Similarity > 1 {
+		return fmt.Errorf("learn similarity are invalid")
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// HostSourceMessage - адрес устройства берется из сообщения,
+	// адрес отправителя пакета используется, если шаблон не содержит адреса.
+	HostSourceMessage = "message"
+	// HostSourcePacket - адрес отправителя пакета имеет приоритет над адресом из сообщения.
+	HostSourcePacket = "packet"
+	// defaultSocketMode - права доступа к локальному сокету по умолчанию (как у /dev/log).
+	defaultSocketMode = 0666
+)
+
+// Listener - параметры обработчика входящих сообщений.
+// Незаданные размер буфера, время бездействия, источник адреса устройства
+// параметры потоков обработки, набор правил имен интерфейсов и вид обработчика берутся из общих параметров syslog,
+// при отсутствии собственного набора шаблонов используется общий.
+// vendor - набор правил нормализации имен интерфейсов (syslog.interfaces),
+// parser - вид обработчика сообщений (template, json, kv).
+// Для локальных сокетов (protocol: unix, unixgram) listen - путь к файлу сокета,
+// mode - права доступа к нему.
+type Listener struct {
+	Protocol    string        `yaml:"protocol"`
+	Listen      string        `yaml:"listen"`
+	BufSize     int           `yaml:"buf_size"`
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	HostSource  string        `yaml:"host_source"`
+	Workers     int           `yaml:"workers"`
+	QueueSize   int           `yaml:"queue_size"`
+	Sockets     int           `yaml:"sockets"`
+	Batch       int           `yaml:"batch"`
+	ReadBuffer  int           `yaml:"read_buffer"`
+	Mode        os.FileMode   `yaml:"mode"`
+	RateLimit   RateLimit     `yaml:"rate_limit"`
+	Vendor      string        `yaml:"vendor"`
+	Parser      string        `yaml:"parser"`
+	Templates   []string      `yaml:"templates"`
+	TLS         TLS           `yaml:"tls"`
+}
+
+// isValid - проверка корректности параметров обработчика.
+func (l *Listener) isValid() error {
+	if len(l.Listen) == 0 {
+		return fmt.Errorf("listen port are not set")
+	}
+	if l.BufSize < 1 {
+		return fmt.Errorf("buf size are not set")
+	}
+	if l.Workers < 0 || l.QueueSize < 0 {
+		return fmt.Errorf("workers or queue size are invalid")
+	}
+	if l.Sockets < 0 || l.Batch < 0 || l.ReadBuffer < 0 {
+		return fmt.Errorf("udp sockets, batch or read buffer size are invalid")
+	}
+	if err := l.RateLimit.isValid(); err != nil {
+		return err
+	}
+	switch l.HostSource {
+	case HostSourceMessage, HostSourcePacket:
+	default:
+		return fmt.Errorf("host source \"%s\" are unknown", l.HostSource)
+	}
+	switch l.Protocol {
+	case "udp", "tcp":
+	case "unix", "unixgram":
+		if l.Mode&^os.ModePerm != 0 {
+			return fmt.Errorf("socket mode %o are invalid", uint32(l.Mode))
+		}
+	case "tls":
+		if _, err := l.TLS.Config(); err != nil {
+			return fmt.Errorf("tls params are invalid - %v", err)
+		}
+	default:
+		return fmt.Errorf("protocol \"%s\" are unknown", l.Protocol)
+	}
+
+	return nil
+}
+
+// RateLimit - ограничение интенсивности приема сообщений (сообщений в секунду)
+// для каждого отправителя и для каждой подсети отправителей.
+// Disabled - отключить ограничение (для обработчика - не использовать общее ограничение).
+type RateLimit struct {
+	Disabled     bool          `yaml:"disabled"`
+	SourceRate   float64       `yaml:"source_rate"`
+	SourceBurst  int           `yaml:"source_burst"`
+	SubnetRate   float64       `yaml:"subnet_rate"`
+	SubnetBurst  int           `yaml:"subnet_burst"`
+	SubnetPrefix int           `yaml:"subnet_prefix"`
+	Interval     time.Duration `yaml:"interval"`
+}
+
+// isValid - проверка корректности параметров ограничения.
+func (r *RateLimit) isValid() error {
+	if r.SourceRate < 0 || r.SourceBurst < 0 || r.SubnetRate < 0 || r.SubnetBurst < 0 {
+		return fmt.Errorf("rate limit are invalid")
+	}
+	if r.SubnetPrefix < 0 || r.SubnetPrefix > 32 {
+		return fmt.Errorf("rate limit subnet prefix %d are invalid", r.SubnetPrefix)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("rate limit interval are invalid")
+	}
+	return nil
+}
+
+// TLS - параметры TLS для приема сообщений (protocol: tls).
+type TLS struct {
+	Cert              string `yaml:"cert"`
+	Key               string `yaml:"key"`
+	CA                string `yaml:"ca"`
+	RequireClientCert bool   `yaml:"require_client_cert"`
+	VerifyClientCert  bool   `yaml:"verify_client_cert"`
+}
+
+// Config - сформировать параметры TLS.
+// Сертификаты клиентов проверяются по набору корневых сертификатов CA
+// (если не указан - используется системный). Требование сертификата без его проверки
+// не допускается - непроверенный сертификат не удостоверяет устройство (поле Peer события).
+func (t *TLS) Config() (*tls.Config, error) {
+	if t.RequireClientCert && !t.VerifyClientCert {
+		return nil, fmt.Errorf("require_client_cert without verify_client_cert are not supported")
+	}
+	cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate err - %v", err)
+	}
+	result := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if len(t.CA) != 0 {
+		buf, err := ioutil.ReadFile(t.CA)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file err - %v", err)
+		}
+		result.ClientCAs = x509.NewCertPool()
+		if !result.ClientCAs.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("no certificates in ca file \"%s\"", t.CA)
+		}
+	}
+	switch {
+	case t.RequireClientCert:
+		result.ClientAuth = tls.RequireAndVerifyClientCert
+	case t.VerifyClientCert:
+		result.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		result.ClientAuth = tls.NoClientCert
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TemplatePack - набор шаблонов оборудования (файл каталога syslog.template_packs).
+// name - имя набора (по умолчанию - имя файла без расширения), vendor, model и version -
+// описание оборудования и версии набора, nets - сети устройств, к сообщениям которых
+// применяется набор (пусто - ко всем), interfaces - набор правил нормализации имен
+// интерфейсов (syslog.interfaces, по умолчанию - правила обработчика входящих сообщений).
+type TemplatePack struct {
+	Name       string   `yaml:"name"`
+	Vendor     string   `yaml:"vendor"`
+	Model      string   `yaml:"model"`
+	Version    string   `yaml:"version"`
+	Nets       []string `yaml:"nets"`
+	Interfaces string   `yaml:"interfaces"`
+	Templates  []string `yaml:"templates"`
+	File       string   `yaml:"-"`
+}
+
+// isValid - проверка корректности набора шаблонов.
+func (p *TemplatePack) isValid() error {
+	if len(p.Templates) == 0 {
+		return fmt.Errorf("templates are not set")
+	}
+	if _, err := p.Networks(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Networks - сети устройств, к сообщениям которых применяется набор.
+func (p *TemplatePack) Networks() ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(p.Nets))
+	for _, n := range p.Nets {
+		_, nwk, err := net.ParseCIDR(n)
+		if err != nil {
+			return nil, fmt.Errorf("net \"%s\" are invalid - %v", n, err)
+		}
+		result = append(result, nwk)
+	}
+	return result, nil
+}
+
+// LoadTemplatePacks - загрузить наборы шаблонов из файлов *.yml и *.yaml каталога dir
+// (в порядке имен файлов).
+func LoadTemplatePacks(dir string) ([]TemplatePack, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read template packs dir err - %v", err)
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if ext := filepath.Ext(f.Name()); !f.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	result := make([]TemplatePack, 0, len(names))
+	exist := make(map[string]string)
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read template pack %s err - %v", file, err)
+		}
+		pack := TemplatePack{}
+		if err := yaml.Unmarshal(buf, &pack); err != nil {
+			return nil, fmt.Errorf("parse template pack %s err - %v", file, err)
+		}
+		pack.File = file
+		if len(pack.Name) == 0 {
+			pack.Name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		if err := pack.isValid(); err != nil {
+			return nil, fmt.Errorf("template pack %s err - %v", file, err)
+		}
+		if prev, ok := exist[pack.Name]; ok {
+			return nil, fmt.Errorf("template pack name \"%s\" of %s are already used by %s", pack.Name, file, prev)
+		}
+		exist[pack.Name] = file
+		result = append(result, pack)
+	}
+	return result, nil
+}
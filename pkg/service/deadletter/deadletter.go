@@ -0,0 +1,260 @@
+// Package deadletter - хранилище сообщений, которые не удалось преобразовать в события.
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ReasonUnmatched - сообщение не совпало ни с одним шаблоном.
+	ReasonUnmatched = "unmatched"
+	// ReasonMalformed - ошибка обработки данных совпавшего шаблона (неверный формат поля).
+	ReasonMalformed = "malformed"
+
+	// defaultMaxSize - максимальный размер файла по умолчанию, байт.
+	defaultMaxSize = 10 << 20
+	// defaultMaxFiles - количество архивных файлов по умолчанию.
+	defaultMaxFiles = 3
+	// defaultQueueSize - размер очереди записи в файл по умолчанию.
+	defaultQueueSize = 1024
+	// reopenInterval - период повторного открытия файла (и вывода ошибки) после ошибки открытия.
+	reopenInterval = 10 * time.Second
+)
+
+// Record - сообщение, которое не удалось преобразовать в событие.
+type Record struct {
+	Time     time.Time `json:"time"`               // время получения
+	Source   string    `json:"source"`             // адрес отправителя
+	Reason   string    `json:"reason"`             // причина (unmatched, malformed)
+	Template string    `json:"template,omitempty"` // идентификатор совпавшего шаблона (для malformed)
+	Error    string    `json:"error"`              // текст ошибки обработчика
+	Text     string    `json:"text"`               // текст сообщения (без заголовка syslog)
+}
+
+// NewRecord - сформировать запись по ошибке обработчика шаблонов.
+func NewRecord(text, source string, err error) *Record {
+	result := &Record{
+		Time:   time.Now(),
+		Source: source,
+		Reason: ReasonMalformed,
+		Error:  err.Error(),
+		Text:   text,
+	}
+	switch e := err.(type) {
+	case *parser.ErrUnknownFormat:
+		result.Reason = ReasonUnmatched
+	case *parser.ErrDataParse:
+		result.Template = e.Template
+	}
+	return result
+}
+
+// Options - параметры хранилища.
+type Options struct {
+	File      string // путь к файлу (пусто - записи только передаются подписчикам)
+	MaxSize   int64  // максимальный размер файла, байт (0 - 10 МБ)
+	MaxFiles  int    // количество архивных файлов file.1 ... file.N (0 - 3)
+	QueueSize int    // размер очереди записи в файл (0 - 1024), при переполнении записи отбрасываются
+}
+
+// Store - хранилище сообщений, которые не удалось преобразовать в события:
+// записи передаются подписчикам и сохраняются в файл (JSON, по записи в строке)
+// с ротацией по размеру. Запись в файл выполняется отдельным потоком через очередь,
+// чтобы не задерживать потоки обработки сообщений.
+type Store struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	// Счетчик записей, не сохраненных в файл (доступ - только через sync/atomic)
+	dropped uint64
+
+	mu     sync.RWMutex
+	subs   map[chan *Record]struct{}
+	queue  chan *Record
+	closed bool
+	done   chan struct{}
+
+	// Данные потока записи в файл
+	file     *os.File
+	size     int64
+	reopen   time.Time // время следующей попытки открытия файла после ошибки
+	failures uint64    // записей, не сохраненных из-за ошибки открытия файла с последнего вывода ошибки
+}
+
+// New - создать новый экземпляр Store.
+func New(opts Options) (*Store, error) {
+	if opts.MaxSize < 0 || opts.MaxFiles < 0 || opts.QueueSize < 0 {
+		return nil, fmt.Errorf("dead letter max size, max files or queue size are invalid")
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = defaultMaxSize
+	}
+	if opts.MaxFiles == 0 {
+		opts.MaxFiles = defaultMaxFiles
+	}
+	if opts.QueueSize == 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	s := &Store{
+		path:     opts.File,
+		maxSize:  opts.MaxSize,
+		maxFiles: opts.MaxFiles,
+		subs:     make(map[chan *Record]struct{}),
+		done:     make(chan struct{}),
+	}
+	if len(s.path) == 0 {
+		close(s.done)
+		return s, nil
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	s.queue = make(chan *Record, opts.QueueSize)
+	go s.run()
+	return s, nil
+}
+
+// open - открыть файл для добавления записей.
+func (s *Store) open() error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open dead letter file \"%s\" err - %v", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat dead letter file \"%s\" err - %v", s.path, err)
+	}
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+// rotate - переименовать текущий файл в file.1 (архивные - со сдвигом номера,
+// самый старый удаляется). Новый файл открывается при записи.
+func (s *Store) rotate() {
+	s.file.Close()
+	s.file = nil
+	os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxFiles))
+	for n := s.maxFiles - 1; n > 0; n-- {
+		os.Rename(fmt.Sprintf("%s.%d", s.path, n), fmt.Sprintf("%s.%d", s.path, n+1))
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		log.Warnf("rotate dead letter file err - %v", err)
+	}
+}
+
+// run - поток записи в файл: сохранять записи из очереди до ее закрытия.
+func (s *Store) run() {
+	defer close(s.done)
+	for rec := range s.queue {
+		s.save(rec)
+	}
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+}
+
+// save - сохранить запись в файл. После ошибки открытия файла повторная попытка
+// выполняется не чаще reopenInterval, записи до нее не сохраняются.
+func (s *Store) save(rec *Record) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		log.Warnf("encode dead letter err - %v", err)
+		atomic.AddUint64(&s.dropped, 1)
+		return
+	}
+	line = append(line, '\n')
+	if s.file != nil && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		s.rotate()
+	}
+	if s.file == nil {
+		now := time.Now()
+		if now.Before(s.reopen) {
+			s.failures++
+			atomic.AddUint64(&s.dropped, 1)
+			return
+		}
+		if err := s.open(); err != nil {
+			if s.failures != 0 {
+				log.Warnf("%v - %d dead letters are not saved", err, s.failures)
+			} else {
+				log.Warnf("%v", err)
+			}
+			s.reopen, s.failures = now.Add(reopenInterval), 1
+			atomic.AddUint64(&s.dropped, 1)
+			return
+		}
+		s.failures = 0
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		log.Warnf("write dead letter err - %v", err)
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Write - передать запись подписчикам и поставить ее в очередь записи в файл
+// (подписчику, не успевающему принимать записи, запись не передается,
+// при переполнении очереди запись не сохраняется).
+func (s *Store) Write(rec *Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.subs {
+		select {
+		case ch <- rec:
+		default:
+		}
+	}
+	if s.queue == nil || s.closed {
+		return
+	}
+	select {
+	case s.queue <- rec:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Dropped - количество записей, не сохраненных в файл
+// (переполнение очереди, ошибка открытия или записи файла).
+func (s *Store) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Subscribe - подписаться на новые записи (size - размер очереди подписчика).
+func (s *Store) Subscribe(size int) chan *Record {
+	ch := make(chan *Record, size)
+	s.mu.Lock()
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe - отменить подписку на новые записи.
+func (s *Store) Unsubscribe(ch chan *Record) {
+	s.mu.Lock()
+	delete(s.subs, ch)
+	s.mu.Unlock()
+}
+
+// Close - сохранить записи очереди и закрыть файл хранилища.
+func (s *Store) Close() {
+	s.mu.Lock()
+	if !s.closed && s.queue != nil {
+		close(s.queue)
+	}
+	s.closed = true
+	s.mu.Unlock()
+	<-s.done
+}
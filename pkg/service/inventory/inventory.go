@@ -0,0 +1,225 @@
+// Package inventory - справочник устройств для дополнения событий
+// именем, площадкой, производителем, моделью и ролью устройства.
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// Device - запись справочника устройств.
+type Device struct {
+	Addr     string `yaml:"addr" json:"addr"`         // IP-адрес устройства
+	Hostname string `yaml:"hostname" json:"hostname"` // имя устройства
+	Site     string `yaml:"site" json:"site"`         // площадка (узел связи)
+	Vendor   string `yaml:"vendor" json:"vendor"`     // производитель
+	Model    string `yaml:"model" json:"model"`       // модель
+	Role     string `yaml:"role" json:"role"`         // роль устройства
+}
+
+// Options - параметры справочника устройств.
+type Options struct {
+	// File - путь к файлу справочника, формат определяется расширением:
+	// .csv (первая строка - имена столбцов), .yml/.yaml, .json (список записей).
+	File string
+	// Reload - интервал проверки изменения файла справочника (0 - не проверять).
+	Reload time.Duration
+}
+
+// Inventory - справочник устройств.
+type Inventory struct {
+	opts    Options
+	mu      sync.RWMutex
+	devices map[string]*pb.Device
+	modTime time.Time
+	size    int64
+	closed  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New - загрузить справочник устройств. Если задан интервал проверки -
+// файл перечитывается при изменении (при ошибке загрузки сохраняются прежние данные).
+func New(opts Options) (*Inventory, error) {
+	inv := &Inventory{
+		opts:    opts,
+		devices: make(map[string]*pb.Device),
+		closed:  make(chan struct{}),
+	}
+	if err := inv.Reload(); err != nil {
+		return nil, err
+	}
+	if opts.Reload > 0 {
+		inv.wg.Add(1)
+		go inv.watch()
+	}
+	return inv, nil
+}
+
+// Reload - перечитать файл справочника.
+func (inv *Inventory) Reload() error {
+	info, err := os.Stat(inv.opts.File)
+	if err != nil {
+		return fmt.Errorf("read inventory err - %v", err)
+	}
+	devices, err := Load(inv.opts.File)
+	if err != nil {
+		return err
+	}
+	inv.mu.Lock()
+	inv.devices, inv.modTime, inv.size = devices, info.ModTime(), info.Size()
+	inv.mu.Unlock()
+	log.Infof("device inventory %s is loaded - %d devices", inv.opts.File, len(devices))
+	return nil
+}
+
+// watch - перечитывать файл справочника при изменении времени модификации или размера.
+func (inv *Inventory) watch() {
+	defer inv.wg.Done()
+	ticker := time.NewTicker(inv.opts.Reload)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-inv.closed:
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(inv.opts.File)
+		if err != nil {
+			log.Warnf("check device inventory err - %v", err)
+			continue
+		}
+		inv.mu.RLock()
+		changed := !info.ModTime().Equal(inv.modTime) || info.Size() != inv.size
+		inv.mu.RUnlock()
+		if !changed {
+			continue
+		}
+		if err := inv.Reload(); err != nil {
+			log.Errorf("reload device inventory err - %v", err)
+		}
+	}
+}
+
+// Lookup - найти устройство по IP-адресу (nil - устройство отсутствует в справочнике).
+func (inv *Inventory) Lookup(addr string) *pb.Device {
+	if ip := net.ParseIP(addr); ip != nil {
+		addr = ip.String()
+	}
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return inv.devices[addr]
+}
+
+// Enrich - дополнить событие данными устройства из справочника.
+func (inv *Inventory) Enrich(event *pb.Event) {
+	if event.Device == nil {
+		event.Device = inv.Lookup(event.Host)
+	}
+}
+
+// Close - завершить проверку изменения файла справочника.
+func (inv *Inventory) Close() {
+	select {
+	case <-inv.closed:
+	default:
+		close(inv.closed)
+	}
+	inv.wg.Wait()
+}
+
+// Load - загрузить записи справочника из файла (IP-адрес - данные устройства).
+func Load(file string) (map[string]*pb.Device, error) {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory err - %v", err)
+	}
+	var devices []Device
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".csv":
+		devices, err = parseCSV(bytes.NewReader(buf))
+	case ".yml", ".yaml":
+		err = yaml.UnmarshalStrict(buf, &devices)
+	case ".json":
+		err = json.Unmarshal(buf, &devices)
+	default:
+		return nil, fmt.Errorf("inventory format \"%s\" are unknown", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse inventory %s err - %v", file, err)
+	}
+
+	result := make(map[string]*pb.Device, len(devices))
+	for k, d := range devices {
+		ip := net.ParseIP(strings.TrimSpace(d.Addr))
+		if ip == nil {
+			return nil, fmt.Errorf("inventory %s device #%d addr \"%s\" are invalid", file, k+1, d.Addr)
+		}
+		result[ip.String()] = &pb.Device{
+			Hostname: d.Hostname,
+			Site:     d.Site,
+			Vendor:   d.Vendor,
+			Model:    d.Model,
+			Role:     d.Role,
+		}
+	}
+	return result, nil
+}
+
+// parseCSV - разобрать справочник в формате CSV: первая строка - имена столбцов
+// (addr, hostname, site, vendor, model, role, без учета регистра, прочие столбцы пропускаются).
+func parseCSV(r io.Reader) ([]Device, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header err - %v", err)
+	}
+	columns := make(map[string]int)
+	for k, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = k
+	}
+	if _, ok := columns["addr"]; !ok {
+		return nil, fmt.Errorf("addr column are not set")
+	}
+
+	result := make([]Device, 0)
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if k, ok := columns[name]; ok && k < len(rec) {
+				return strings.TrimSpace(rec[k])
+			}
+			return ""
+		}
+		result = append(result, Device{
+			Addr:     field("addr"),
+			Hostname: field("hostname"),
+			Site:     field("site"),
+			Vendor:   field("vendor"),
+			Model:    field("model"),
+			Role:     field("role"),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// converter - преобразователь значения произвольного поля к нормализованному виду.
+type converter func(string) (string, error)
+
+var (
+	// converterKeyword - допустимые типы произвольных полей.
+	converterKeyword = map[string]converter{
+		"string":   nil,
+		"int":      convertInt,
+		"ip":       convertIP,
+		"mac":      convertMAC,
+		"duration": convertDuration,
+	}
+)
+
+// newConverters - сформировать преобразователи значений произвольных полей
+// по описанию "имя поля - тип".
+func newConverters(fields map[string]string) (map[string]converter, error) {
+	result := make(map[string]converter, len(fields))
+	for name, typ := range fields {
+		conv, exist := converterKeyword[typ]
+		if !exist {
+			return nil, fmt.Errorf("field %s type \"%s\" are unknown", name, typ)
+		}
+		if _, ok := fieldType(name); ok {
+			return nil, fmt.Errorf("field %s type can not be changed", name)
+		}
+		result[name] = conv
+	}
+	return result, nil
+}
+
+// convertInt - целое число (десятичная запись).
+func convertInt(s string) (string, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("integer has invalid format")
+	}
+	return strconv.FormatInt(v, 10), nil
+}
+
+// convertIP - IP-адрес.
+func convertIP(s string) (string, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		return ip.String(), nil
+	}
+	return "", fmt.Errorf("ip address has invalid format")
+}
+
+// convertMAC - MAC-адрес (xx:xx:xx:xx:xx:xx), допустимы записи
+// через "-" и через "." (xxxx.xxxx.xxxx).
+func convertMAC(s string) (string, error) {
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return "", fmt.Errorf("mac address has invalid format")
+	}
+	return mac.String(), nil
+}
+
+// convertDuration - интервал времени (1h2m3s), число без единиц измерения - секунды.
+func convertDuration(s string) (string, error) {
+	if v, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return (time.Duration(v) * time.Second).String(), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", fmt.Errorf("duration has invalid format")
+	}
+	return d.String(), nil
+}
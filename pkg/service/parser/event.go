@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+const (
+	// maxSeverity - минимальный уровень важности (debug).
+	maxSeverity = 7
+)
+
+var (
+	// builtinTypes - встроенные типы событий шаблонов.
+	builtinTypes = []eventType{
+		{EventType: EventType{Name: "ignore", Description: "message without event", Severity: 7}, legacy: pb.EventType_Unknown},
+		{EventType: EventType{Name: "link_up", Description: "port link is up", Severity: 5}, legacy: pb.EventType_PortUp},
+		{EventType: EventType{Name: "link_down", Description: "port link is down", Severity: 4}, legacy: pb.EventType_PortDown},
+		{EventType: EventType{Name: "loopdetect", Description: "port is disabled by loop detection", Severity: 3}, legacy: pb.EventType_PortLoopDetect},
+	}
+)
+
+// EventType - тип события, заданный в конфигурации.
+type EventType struct {
+	Name        string // имя типа (указывается в начале шаблона)
+	Description string // описание типа
+	Severity    uint32 // уровень важности (0 - emergency ... 7 - debug)
+}
+
+// eventType - тип события шаблона.
+type eventType struct {
+	EventType
+	legacy pb.EventType // значение перечисления EventType (Unknown - для типов из конфигурации)
+}
+
+// newEventTypes - сформировать набор типов событий - встроенные и заданные в конфигурации.
+// Тип из конфигурации с именем встроенного типа заменяет его описание и уровень важности.
+func newEventTypes(types []EventType) (map[string]*eventType, error) {
+	result := make(map[string]*eventType, len(builtinTypes)+len(types))
+	for k := range builtinTypes {
+		t := builtinTypes[k]
+		result[t.Name] = &t
+	}
+	defined := make(map[string]struct{}, len(types))
+	for _, v := range types {
+		if !patternName.MatchString(v.Name) {
+			return nil, fmt.Errorf("event type name \"%s\" are invalid", v.Name)
+		}
+		if _, exist := defined[v.Name]; exist {
+			return nil, fmt.Errorf("event type %s are duplicated", v.Name)
+		}
+		if v.Severity > maxSeverity {
+			return nil, fmt.Errorf("event type %s severity %d are invalid", v.Name, v.Severity)
+		}
+		defined[v.Name] = struct{}{}
+		t := &eventType{EventType: v}
+		if builtin, exist := result[v.Name]; exist {
+			t.legacy = builtin.legacy
+		}
+		result[v.Name] = t
+	}
+
+	return result, nil
+}
+
+// lookupEventType - определить тип события по имени из шаблона.
+func lookupEventType(types map[string]*eventType, name string) (*eventType, error) {
+	if t, exist := types[name]; exist {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown event type - %s", name)
+}
+
+// newEvent - создать событие заданного типа.
+func (t *eventType) newEvent() *pb.Event {
+	return &pb.Event{
+		Type:     t.legacy,
+		TypeName: t.Name,
+		Severity: t.Severity,
+	}
+}
@@ -1,14 +1,20 @@
 package parser
 
+import (
+	"regexp"
+)
+
 // Допустимые типы данных полей сообщения.
 // указаны в качестве примера, количество и значение должны совпадать
-// с массивом fileKeyword.
+// с массивом fileKeyword (кроме attribute и ignoreText).
 const (
 	plainText = iota
 	deviceAddr
 	devicePort
 	portSpeed
 	portDuplex
+	attribute  // произвольное поле ($name$) - сохраняется в Attributes события
+	ignoreText // любое слово ($ignore$)
 )
 
 var (
@@ -20,27 +26,73 @@ var (
 		"$port_speed$",
 		"$port_duplex$",
 	}
+
+	// attributeKeyword - произвольное поле шаблона.
+	attributeKeyword = regexp.MustCompile(`^\$(\w+)\$$`)
 )
 
+// fieldOptions - параметры преобразования значений полей шаблонов.
+type fieldOptions struct {
+	convs   map[string]converter // преобразователи значений произвольных полей по именам
+	ifRules ifRules              // правила нормализации имен интерфейсов
+}
+
 // newTextField - создать новое поле на основе данных шаблона.
-func newTextField(text string) *textField {
+func newTextField(text string, opts *fieldOptions) *textField {
 	for k, v := range fieldKeyword {
 		if text == v {
-			return &textField{
-				dataType: k,
+			if k == plainText {
+				return &textField{dataType: ignoreText}
 			}
+			return newDataField(k, opts)
 		}
 	}
+	if args := attributeKeyword.FindStringSubmatch(text); args != nil {
+		return newAttributeField(args[1], opts)
+	}
 
 	return &textField{
 		text: text,
 	}
 }
 
+// newDataField - создать поле данных события заданного типа.
+func newDataField(dataType int, opts *fieldOptions) *textField {
+	result := &textField{
+		dataType: dataType,
+	}
+	if dataType == devicePort {
+		result.ifRules = opts.ifRules
+	}
+	return result
+}
+
+// newAttributeField - создать произвольное поле с заданным именем.
+func newAttributeField(name string, opts *fieldOptions) *textField {
+	return &textField{
+		dataType: attribute,
+		name:     name,
+		convert:  opts.convs[name],
+	}
+}
+
+// fieldType - определить тип данных по имени поля (без символов "$").
+func fieldType(name string) (int, bool) {
+	for k, v := range fieldKeyword {
+		if v == "$"+name+"$" {
+			return k, true
+		}
+	}
+	return plainText, false
+}
+
 // textField - поле (слово) текстового шаблона.
 type textField struct {
-	dataType int    // тип данных (0 - plainText)
-	text     string // текст для сверки (только в случае если dataType == 0)
+	dataType int       // тип данных (0 - plainText)
+	text     string    // текст для сверки (только в случае если dataType == 0)
+	name     string    // имя поля (только в случае если dataType == attribute)
+	convert  converter // преобразователь значения поля (nil - значение сохраняется без изменений)
+	ifRules  ifRules   // правила нормализации имени интерфейса (только в случае если dataType == devicePort)
 }
 
 // match - сверить слово с тексовым шаблоном - в случае если
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxPatternDepth - максимальная глубина вложенности именованных выражений.
+	maxPatternDepth = 16
+)
+
+var (
+	// patternRef - ссылка на именованное выражение - %{NAME} или %{NAME:field}.
+	patternRef = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+	// patternName - допустимое имя именованного выражения.
+	patternName = regexp.MustCompile(`^\w+$`)
+	// ipv6Pattern - адрес IPv6 (H - группа из 1-4 шестнадцатеричных цифр): адрес IPv4
+	// в младших 32 битах, полная запись из 8 групп, либо сокращенная запись с "::".
+	// Границы слова исключают совпадение с временем (10:15:20) и MAC-адресом (00:11:22:33:44:55).
+	ipv6Pattern = strings.NewReplacer("H", `[0-9A-Fa-f]{1,4}`).Replace(
+		`(?:\b(?:H:){6}|(?:\bH(?::H){0,4})?::(?:H:){0,4})%{IPV4}` +
+			`|\b(?:H:){7}H\b` +
+			`|(?:\bH(?::H){0,6})?::(?:H(?::H){0,6}\b)?`)
+
+	// builtinPatterns - встроенная библиотека именованных выражений.
+	builtinPatterns = map[string]string{
+		"INT":        `[+-]?\d+`,
+		"NUMBER":     `[+-]?\d+(?:\.\d+)?`,
+		"WORD":       `\w+`,
+		"NOTSPACE":   `\S+`,
+		"SPACE":      `\s*`,
+		"DATA":       `.*?`,
+		"GREEDYDATA": `.*`,
+		"IPV4":       `(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)`,
+		"IPV6":       ipv6Pattern,
+		"IP":         `%{IPV4}|%{IPV6}`,
+		"MAC":        `(?:[0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}|(?:[0-9A-Fa-f]{4}\.){2}[0-9A-Fa-f]{4}`,
+		"IFNAME":     `[A-Za-z][A-Za-z-]*\d+(?:[/:.]\d+)*`,
+		"SPEED":      `\d+(?:\.\d+)?\s?(?i:[kmgt]?b(?:it)?(?:/s|ps)?|[kmgt])?|(?i:auto|unknown)`,
+		"DUPLEX":     `(?i:full|half)(?:-duplex)?`,
+	}
+)
+
+// library - библиотека именованных выражений (встроенные и пользовательские).
+type library map[string]string
+
+// newLibrary - создать библиотеку выражений, пользовательские выражения
+// дополняют встроенные (или заменяют их при совпадении имени).
+func newLibrary(patterns map[string]string) (library, error) {
+	result := make(library, len(builtinPatterns)+len(patterns))
+	for name, expr := range builtinPatterns {
+		result[name] = expr
+	}
+	for name, expr := range patterns {
+		if !patternName.MatchString(name) {
+			return nil, fmt.Errorf("pattern name \"%s\" are invalid", name)
+		}
+		result[name] = expr
+	}
+	// Все выражения проверяются заранее - ошибка в неиспользуемом выражении
+	// обнаруживается при запуске сервиса.
+	for name := range patterns {
+		expr, err := result.expand("%{" + name + "}")
+		if err == nil {
+			_, err = regexp.Compile(expr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s err - %v", name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// expand - заменить ссылки на именованные выражения их содержимым:
+// %{NAME} - группой без захвата, %{NAME:field} - именованной группой field.
+func (l library) expand(expr string) (string, error) {
+	return l.expandDepth(expr, 0)
+}
+
+// expandDepth - рекурсивная замена ссылок с ограничением глубины вложенности.
+func (l library) expandDepth(expr string, depth int) (string, error) {
+	if depth > maxPatternDepth {
+		return "", fmt.Errorf("pattern nesting is too deep (recursive pattern?)")
+	}
+	var err error
+	result := patternRef.ReplaceAllStringFunc(expr, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		args := patternRef.FindStringSubmatch(ref)
+		body, ok := l[args[1]]
+		if !ok {
+			err = fmt.Errorf("pattern %s are unknown", args[1])
+			return ""
+		}
+		body, err = l.expandDepth(body, depth+1)
+		if len(args[2]) != 0 {
+			return "(?P<" + args[2] + ">" + body + ")"
+		}
+		return "(?:" + body + ")"
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
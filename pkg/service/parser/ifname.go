@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+var (
+	// ifnameParts - имя интерфейса: тип (буквы), необязательный разделитель и номер.
+	ifnameParts = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*?)[-_ ]?([0-9].*)$`)
+
+	// defaultIfRules - правила нормализации имен интерфейсов по умолчанию
+	// (сокращение типа в нижнем регистре - полное имя).
+	defaultIfRules = map[string]string{
+		"fa":                   "FastEthernet",
+		"fastethernet":         "FastEthernet",
+		"gi":                   "GigabitEthernet",
+		"gigabitethernet":      "GigabitEthernet",
+		"te":                   "TenGigabitEthernet",
+		"tengigabitethernet":   "TenGigabitEthernet",
+		"twe":                  "TwentyFiveGigE",
+		"twentyfivegige":       "TwentyFiveGigE",
+		"fo":                   "FortyGigabitEthernet",
+		"fortygigabitethernet": "FortyGigabitEthernet",
+		"hu":                   "HundredGigE",
+		"hundredgige":          "HundredGigE",
+		"eth":                  "Ethernet",
+		"ethernet":             "Ethernet",
+		"po":                   "Port-channel",
+		"port-channel":         "Port-channel",
+		"lo":                   "Loopback",
+		"loopback":             "Loopback",
+		"vl":                   "Vlan",
+		"vlan":                 "Vlan",
+	}
+)
+
+// ifRules - правила нормализации имен интерфейсов (тип в нижнем регистре - полное имя типа).
+type ifRules map[string]string
+
+// newIfRules - сформировать правила нормализации - правила по умолчанию,
+// дополненные (или замененные) правилами rules.
+func newIfRules(rules map[string]string) ifRules {
+	result := make(ifRules, len(defaultIfRules)+len(rules))
+	for k, v := range defaultIfRules {
+		result[k] = v
+	}
+	for k, v := range rules {
+		result[strings.ToLower(k)] = v
+	}
+	return result
+}
+
+// normalize - сформировать нормализованное имя интерфейса и его компоненты.
+// Тип интерфейса заменяется полным именем по правилам, номер - сохраняется без изменений,
+// имя интерфейса неизвестного типа не изменяется.
+// Компоненты номера: один - порт, два - слот/порт, три и более - слот/.../модуль/порт
+// (номер субинтерфейса после "." не учитывается).
+func (r ifRules) normalize(text string) (string, *pb.InterfaceID) {
+	id := &pb.InterfaceID{}
+	number := text
+	if args := ifnameParts.FindStringSubmatch(text); args != nil {
+		id.Type = args[1]
+		number = args[2]
+		if name, exist := r[strings.ToLower(args[1])]; exist {
+			id.Type = name
+			text = name + number
+		}
+	}
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		number = number[:i]
+	}
+	nums := digitsOnly.FindAllString(number, -1)
+	parts := make([]uint32, 0, len(nums))
+	for _, v := range nums {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			break
+		}
+		parts = append(parts, uint32(n))
+	}
+	switch {
+	case len(parts) >= 3:
+		id.Module = parts[len(parts)-2]
+		fallthrough
+	case len(parts) == 2:
+		id.Slot = parts[0]
+		fallthrough
+	case len(parts) == 1:
+		id.Port = parts[len(parts)-1]
+	}
+
+	return text, id
+}
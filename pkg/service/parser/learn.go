@@ -0,0 +1,374 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultMaxClusters - максимальное количество групп сообщений по умолчанию.
+	defaultMaxClusters = 1000
+	// defaultSimilarity - минимальная доля совпадающих слов сообщения и группы по умолчанию.
+	defaultSimilarity = 0.5
+	// defaultLearnDepth - количество первых слов, по которым отбираются группы, по умолчанию.
+	defaultLearnDepth = 2
+	// defaultExamples - количество сохраняемых примеров сообщений группы по умолчанию.
+	defaultExamples = 3
+	// wildcardToken - изменяемое слово в структуре группы сообщений.
+	wildcardToken = "<*>"
+)
+
+// Признаки возможного типа поля изменяемого слова группы.
+const (
+	kindAddr = 1 << iota
+	kindPort
+	kindSpeed
+	kindDuplex
+)
+
+var (
+	// learnPort - имя или номер порта (5, 1/0/5, Ethernet1/0/5, Gi0/1.100).
+	learnPort = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z-]*)?[0-9]+(?:[/:][0-9]+)*(?:\.[0-9]+)?,?$`)
+	// learnSpeed - скорость порта (100, 100MB, 2.5G, 25Gb/s, 100000Mbps, (speed:10G, auto).
+	learnSpeed = regexp.MustCompile(`(?i)^\(?(?:speed:)?(?:[0-9]+(?:\.[0-9]+)?(?:[kmgt][a-z/]*)?|auto),?\)?$`)
+	// learnDuplex - режим дуплекса (full, HALF-duplex, duplex:full)).
+	learnDuplex = regexp.MustCompile(`(?i)^\(?(?:duplex:)?(?:full|half)(?:-duplex)?,?\)?$`)
+	// learnNumber - число (слово, содержащее только цифры и разделители).
+	learnNumber = regexp.MustCompile(`^[.:/,-]*[0-9][0-9.:/,-]*$`)
+)
+
+// LearnOptions - параметры группировки сообщений, не совпавших с шаблонами.
+type LearnOptions struct {
+	MaxClusters int     // максимальное количество групп (0 - 1000), при переполнении вытесняется давно не обновлявшаяся группа
+	Similarity  float64 // минимальная доля совпадающих слов сообщения и группы (0 - 0.5)
+	Depth       int     // количество первых слов сообщения, по которым отбираются группы (0 - 2)
+	Examples    int     // количество сохраняемых примеров сообщений группы (0 - 3)
+}
+
+// Suggestion - группа сообщений, не совпавших с шаблонами, и предлагаемый шаблон для них.
+type Suggestion struct {
+	Template string   // шаблон в формате обработчика ("тип ~ слово $поле$ ...")
+	Pattern  string   // структура сообщений группы (<*> - изменяемое слово)
+	Count    uint64   // количество сообщений группы
+	Examples []string // примеры сообщений группы
+}
+
+// Learner - группировка сообщений, не совпавших ни с одним шаблоном, по структуре
+// (алгоритм Drain): сообщения одной длины с одинаковыми первыми словами сравниваются
+// с группами по доле совпадающих слов, несовпадающие слова группы становятся изменяемыми.
+// По группам формируются шаблоны для обработчика, изменяемым словам назначаются поля
+// device_addr, device_port, port_speed, port_duplex по виду их значений.
+type Learner struct {
+	maxClusters int
+	similarity  float64
+	depth       int
+	examples    int
+
+	mu       sync.Mutex
+	root     map[int]*learnNode
+	clusters []*cluster
+	seq      uint64
+}
+
+// learnNode - узел дерева групп: переход по слову сообщения, в листе - группы.
+// Узлы без групп и дочерних узлов удаляются (сообщения поступают от непроверенных
+// отправителей - размер дерева ограничивается количеством групп).
+type learnNode struct {
+	children map[string]*learnNode
+	clusters []*cluster
+	parent   *learnNode // родительский узел (nil - узел первого уровня)
+	key      string     // слово перехода из родительского узла
+	length   int        // длина сообщений (для узла первого уровня)
+}
+
+// cluster - группа сообщений одной структуры.
+type cluster struct {
+	tokens   []string // слова группы (wildcardToken - изменяемое слово)
+	kinds    []int    // возможные типы полей каждого слова по всем сообщениям группы
+	count    uint64
+	examples []string
+	last     uint64 // порядковый номер последнего сообщения группы
+	node     *learnNode
+}
+
+// NewLearner - создать новый экземпляр Learner.
+func NewLearner(opts LearnOptions) (*Learner, error) {
+	if opts.MaxClusters < 0 || opts.Depth < 0 || opts.Examples < 0 || opts.Similarity < 0 || opts.Similarity > 1 {
+		return nil, fmt.Errorf("learn params are invalid")
+	}
+	if opts.MaxClusters == 0 {
+		opts.MaxClusters = defaultMaxClusters
+	}
+	if opts.Similarity == 0 {
+		opts.Similarity = defaultSimilarity
+	}
+	if opts.Depth == 0 {
+		opts.Depth = defaultLearnDepth
+	}
+	if opts.Examples == 0 {
+		opts.Examples = defaultExamples
+	}
+
+	return &Learner{
+		maxClusters: opts.MaxClusters,
+		similarity:  opts.Similarity,
+		depth:       opts.Depth,
+		examples:    opts.Examples,
+		root:        make(map[int]*learnNode),
+	}, nil
+}
+
+// Add - добавить сообщение в подходящую по структуре группу или создать новую группу.
+func (l *Learner) Add(text string) {
+	tokens := strings.Fields(text)
+	if len(tokens) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	node := l.leaf(tokens)
+	c := l.match(node, tokens)
+	if c == nil {
+		if len(l.clusters) >= l.maxClusters {
+			// Вытеснение может удалить лист сообщения - лист создается заново.
+			l.evict()
+			node = l.leaf(tokens)
+		}
+		c = newCluster(tokens, node)
+		node.clusters = append(node.clusters, c)
+		l.clusters = append(l.clusters, c)
+	} else {
+		c.merge(tokens)
+	}
+	c.count++
+	c.last = l.seq
+	if len(c.examples) < l.examples {
+		c.examples = append(c.examples, text)
+	}
+}
+
+// leaf - найти (создать) лист дерева групп по длине и первым словам сообщения.
+func (l *Learner) leaf(tokens []string) *learnNode {
+	node, exist := l.root[len(tokens)]
+	if !exist {
+		node = &learnNode{children: make(map[string]*learnNode), length: len(tokens)}
+		l.root[len(tokens)] = node
+	}
+	for i := 0; i < l.depth && i < len(tokens); i++ {
+		key := tokens[i]
+		if variable(key) {
+			key = wildcardToken
+		}
+		next, exist := node.children[key]
+		if !exist {
+			next = &learnNode{children: make(map[string]*learnNode), parent: node, key: key}
+			node.children[key] = next
+		}
+		node = next
+	}
+	return node
+}
+
+// match - найти группу листа с наибольшей долей совпадающих слов
+// (не менее порога), при равенстве - с большим количеством изменяемых слов.
+// Изменяемые слова группы, которым соответствуют изменяемые слова сообщения, не учитываются.
+func (l *Learner) match(node *learnNode, tokens []string) *cluster {
+	var result *cluster
+	best, bestVars := -1.0, -1
+	for _, c := range node.clusters {
+		equal, vars, total := 0, 0, 0
+		for i, t := range c.tokens {
+			if t == wildcardToken {
+				vars++
+				if variable(tokens[i]) {
+					continue
+				}
+			}
+			total++
+			if t == tokens[i] {
+				equal++
+			}
+		}
+		sim := 1.0
+		if total != 0 {
+			sim = float64(equal) / float64(total)
+		}
+		if sim > best || (sim == best && vars > bestVars) {
+			result, best, bestVars = c, sim, vars
+		}
+	}
+	if best < l.similarity {
+		return nil
+	}
+	return result
+}
+
+// evict - удалить давно не обновлявшуюся группу и опустевшие узлы дерева.
+func (l *Learner) evict() {
+	k := 0
+	for i, c := range l.clusters {
+		if c.last < l.clusters[k].last {
+			k = i
+		}
+	}
+	c := l.clusters[k]
+	l.clusters = append(l.clusters[:k], l.clusters[k+1:]...)
+	for i, v := range c.node.clusters {
+		if v == c {
+			c.node.clusters = append(c.node.clusters[:i], c.node.clusters[i+1:]...)
+			break
+		}
+	}
+	for node := c.node; node != nil && len(node.clusters) == 0 && len(node.children) == 0; node = node.parent {
+		if node.parent == nil {
+			delete(l.root, node.length)
+		} else {
+			delete(node.parent.children, node.key)
+		}
+	}
+}
+
+// Suggest - вернуть группы, содержащие не менее minCount сообщений, с предлагаемыми
+// шаблонами, по убыванию количества сообщений (limit - максимальное количество, 0 - все).
+func (l *Learner) Suggest(minCount uint64, limit int) []Suggestion {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make([]Suggestion, 0)
+	for _, c := range l.clusters {
+		if c.count < minCount {
+			continue
+		}
+		result = append(result, Suggestion{
+			Template: c.template(),
+			Pattern:  strings.Join(c.tokens, " "),
+			Count:    c.count,
+			Examples: append([]string(nil), c.examples...),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// newCluster - создать группу по первому сообщению,
+// слова с адресами, номерами портов и числами сразу считаются изменяемыми.
+func newCluster(tokens []string, node *learnNode) *cluster {
+	c := &cluster{
+		tokens: make([]string, len(tokens)),
+		kinds:  make([]int, len(tokens)),
+		node:   node,
+	}
+	for i, t := range tokens {
+		c.tokens[i] = t
+		if variable(t) {
+			c.tokens[i] = wildcardToken
+		}
+		c.kinds[i] = tokenKind(t)
+	}
+	return c
+}
+
+// merge - дополнить группу сообщением: несовпадающие слова становятся изменяемыми.
+func (c *cluster) merge(tokens []string) {
+	for i, t := range tokens {
+		if c.tokens[i] != t {
+			c.tokens[i] = wildcardToken
+		}
+		c.kinds[i] &= tokenKind(t)
+	}
+}
+
+// template - сформировать шаблон обработчика для группы. Каждое поле события
+// назначается первому подходящему изменяемому слову, прочие изменяемые слова - $ignore$.
+// Неизменяемые слова с символами разметки шаблона ($поле$, ~re~) также заменяются на $ignore$.
+// Тип события определяется по неизменяемым словам (down, up, loop) при наличии порта.
+func (c *cluster) template() string {
+	fields := []struct {
+		kind int
+		name string
+	}{
+		{kindAddr, "$device_addr$"},
+		{kindPort, "$device_port$"},
+		{kindSpeed, "$port_speed$"},
+		{kindDuplex, "$port_duplex$"},
+	}
+	used := 0
+	words := make([]string, len(c.tokens))
+	for i, t := range c.tokens {
+		words[i] = t
+		if t != wildcardToken {
+			if strings.ContainsAny(t, "$~") {
+				words[i] = "$ignore$"
+			}
+			continue
+		}
+		words[i] = "$ignore$"
+		for _, f := range fields {
+			if c.kinds[i]&f.kind != 0 && used&f.kind == 0 {
+				words[i] = f.name
+				used |= f.kind
+				break
+			}
+		}
+	}
+	return c.eventType(used&kindPort != 0) + patternTypeDelim + strings.Join(words, " ")
+}
+
+// eventType - определить тип события группы по неизменяемым словам: down - link_down,
+// up - link_up, слова с loop - loopdetect (только при наличии порта), иначе - ignore.
+func (c *cluster) eventType(port bool) string {
+	if !port {
+		return "ignore"
+	}
+	words := make(map[string]bool)
+	loop := false
+	for _, t := range c.tokens {
+		w := strings.ToLower(strings.Trim(t, ".,;:!()[]"))
+		if strings.IndexFunc(w, func(r rune) bool { return r < 'a' || r > 'z' }) >= 0 {
+			continue
+		}
+		words[w] = true
+		loop = loop || strings.Contains(w, "loop")
+	}
+	switch {
+	case words["down"]:
+		return "link_down"
+	case words["up"]:
+		return "link_up"
+	case loop:
+		return "loopdetect"
+	}
+	return "ignore"
+}
+
+// variable - признак изменяемого слова (адрес, порт, скорость, число).
+func variable(token string) bool {
+	return tokenKind(token)&(kindAddr|kindPort|kindSpeed) != 0 || learnNumber.MatchString(token)
+}
+
+// tokenKind - возможные типы поля для значения слова.
+func tokenKind(token string) int {
+	result := 0
+	if net.ParseIP(token) != nil {
+		result |= kindAddr
+	}
+	if learnPort.MatchString(token) {
+		result |= kindPort
+	}
+	if learnSpeed.MatchString(token) {
+		result |= kindSpeed
+	}
+	if learnDuplex.MatchString(token) {
+		result |= kindDuplex
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
@@ -17,59 +18,224 @@ const (
 // Parser - интерфейс обработчика текстовых данных.
 type Parser interface {
 	// Parse - преобразовать сообщение в формат события GRPC.
-	Parse(string) (*pb.Event, error)
+	// source - адрес отправителя сообщения, используется в качестве
+	// адреса устройства, если шаблон не содержит поля device_addr.
+	Parse(text, source string) (*pb.Event, error)
+
+	// Stats - статистика использования шаблонов в порядке их указания.
+	Stats() []TemplateStats
+}
+
+// Options - дополнительные параметры обработчика сообщений.
+type Options struct {
+	// Patterns - пользовательские именованные выражения, на которые могут
+	// ссылаться регулярные выражения шаблонов (%{NAME}), дополняют встроенные.
+	Patterns map[string]string
+	// EventTypes - типы событий, дополняющие встроенные (link_up, link_down, loopdetect, ignore).
+	EventTypes []EventType
+	// Fields - типы произвольных полей шаблонов (имя поля - int, ip, mac, duration, string),
+	// значения полей преобразуются к нормализованному виду, поля без типа сохраняются без изменений.
+	Fields map[string]string
+	// Interfaces - правила нормализации имен интерфейсов (сокращение типа - полное имя, Gi - GigabitEthernet),
+	// дополняют правила по умолчанию.
+	Interfaces map[string]string
+	// Linear - сверять сообщение со всеми текстовыми шаблонами той же длины последовательно,
+	// без индекса (для сравнения производительности и отладки).
+	Linear bool
+}
+
+// schema - общие данные шаблонов обработчика: типы событий,
+// библиотека именованных выражений и параметры преобразования полей.
+type schema struct {
+	types  map[string]*eventType
+	lib    library
+	fields *fieldOptions
+}
+
+// newSchema - сформировать общие данные шаблонов по параметрам обработчика.
+func newSchema(opts Options) (*schema, error) {
+	lib, err := newLibrary(opts.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	types, err := newEventTypes(opts.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	convs, err := newConverters(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema{
+		types: types,
+		lib:   lib,
+		fields: &fieldOptions{
+			convs:   convs,
+			ifRules: newIfRules(opts.Interfaces),
+		},
+	}, nil
+}
+
+// complete - дополнить событие адресом отправителя сообщения,
+// если шаблон не содержит адреса устройства.
+func complete(msg *pb.Event, source string) *pb.Event {
+	if len(msg.Host) == 0 {
+		msg.Host = source
+	}
+	return msg
 }
 
 // NewParser - cоздать новый экземпляр Parser.
-// входные данные - набор шаблонов для обработки данных.
-func NewParser(patterns []string) (Parser, error) {
+// входные данные - набор шаблонов для обработки данных:
+// текстовые ("тип ~ слово $поле$ ...") и регулярные выражения
+// с именованными группами ("тип ~re~ выражение"). После типа события
+// могут быть указаны параметры шаблона - "тип [priority=10 on_error=next] ~ ...".
+func NewParser(patterns []string, opts Options) (Parser, error) {
 	if len(patterns) == 0 {
 		return nil, errors.New("no patterns for parser are provided")
 	}
+	s, err := newSchema(opts)
+	if err != nil {
+		return nil, err
+	}
 	result := &textParser{
 		patterns: make(map[int][]*textPattern),
 	}
-	for _, v := range patterns {
+	if !opts.Linear {
+		result.index = newTrieNode()
+	}
+	texts := make([]*textPattern, 0, len(patterns))
+	ids := make(templateIDs)
+	for k, v := range patterns {
+		if args := strings.SplitN(v, patternRegexDelim, 2); len(args) == 2 {
+			name, topts, err := parseHeader(args[0], k)
+			if err == nil {
+				err = ids.assign(&topts, name, patternRegexDelim, args[1])
+			}
+			if err != nil {
+				return nil, fmt.Errorf("new regex pattern - %v", err)
+			}
+			event, err := lookupEventType(s.types, name)
+			if err != nil {
+				return nil, fmt.Errorf("new regex pattern - %v", err)
+			}
+			expr, err := s.lib.expand(args[1])
+			if err != nil {
+				return nil, err
+			}
+			pattern, err := newRegexPattern(event, expr, s.fields)
+			if err != nil {
+				return nil, err
+			}
+			pattern.templateOptions = topts
+			result.regexps = append(result.regexps, pattern)
+			result.templates = append(result.templates, topts)
+			continue
+		}
 		args := strings.SplitN(v, patternTypeDelim, 2)
 		if len(args) != 2 {
 			return nil, errors.New("unknown pattern format")
 		}
-		pattern, err := newTextPattern(args[0], args[1])
+		name, topts, err := parseHeader(args[0], k)
+		if err == nil {
+			err = ids.assign(&topts, name, patternTypeDelim, args[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("new text pattern - %v", err)
+		}
+		event, err := lookupEventType(s.types, name)
+		if err != nil {
+			return nil, fmt.Errorf("new text pattern - %v", err)
+		}
+		pattern, err := newTextPattern(event, args[1], s.fields)
 		if err != nil {
 			return nil, err
 		}
-		arr, exist := result.patterns[len(pattern.fields)]
-		if !exist {
-			arr = make([]*textPattern, 0)
+		pattern.templateOptions = topts
+		texts = append(texts, pattern)
+		result.templates = append(result.templates, topts)
+		if result.index != nil {
+			result.index.insert(pattern)
+			continue
 		}
-		arr = append(arr, pattern)
-		result.patterns[len(pattern.fields)] = arr
+		result.patterns[len(pattern.fields)] = append(result.patterns[len(pattern.fields)], pattern)
+	}
+	for _, arr := range result.patterns {
+		sortTextPatterns(arr)
 	}
+	sort.SliceStable(result.regexps, func(i, j int) bool {
+		return result.regexps[i].before(result.regexps[j].templateOptions)
+	})
+	warnShadowed(texts, result.regexps, patterns)
 	log.Debugf("defined %d text parser patterns", len(patterns))
 
 	return result, nil
 }
 
 // textParser - реализация интерфейса Parser.
+// Сообщение сверяется с шаблонами по убыванию приоритета, при равном приоритете -
+// с текстовыми шаблонами, затем - с регулярными выражениями, в порядке их указания.
+// Текстовые шаблоны отбираются по префиксному дереву слов (index),
+// либо, при Options.Linear, - по количеству слов (patterns).
+// Ошибка обработки данных прекращает разбор, если для шаблона не указано on_error=next.
 type textParser struct {
-	patterns map[int][]*textPattern
+	patterns  map[int][]*textPattern
+	index     *trieNode
+	regexps   []*regexPattern
+	templates []templateOptions // параметры шаблонов в порядке указания
+}
+
+// Stats - статистика использования шаблонов в порядке их указания.
+func (x *textParser) Stats() []TemplateStats {
+	return snapshots(x.templates)
+}
+
+// candidates - текстовые шаблоны, которые могут совпасть с сообщением, в порядке сверки.
+func (x *textParser) candidates(fields []string) []*textPattern {
+	if x.index != nil {
+		return x.index.lookup(fields)
+	}
+	return x.patterns[len(fields)]
 }
 
 // Parse - преобразовать сообщение в формат события GRPC.
-func (x *textParser) Parse(text string) (*pb.Event, error) {
+func (x *textParser) Parse(text, source string) (*pb.Event, error) {
 	fields := strings.Fields(text)
-	if arr, exist := x.patterns[len(fields)]; exist {
-		for _, pattern := range arr {
-			msg, err := pattern.unmarshal(fields...)
-			if err == nil {
-				return msg, nil
-			}
-			if err != ErrNotMatch {
-				log.Warnf("parse err - msg %s - %v", text, err)
-				return nil, err
-			}
+	texts := x.candidates(fields)
+	regexps := x.regexps
+	var failed error
+	for len(texts) > 0 || len(regexps) > 0 {
+		var msg *pb.Event
+		var err error
+		var opts templateOptions
+		if len(regexps) == 0 || (len(texts) > 0 && texts[0].priority >= regexps[0].priority) {
+			msg, err = texts[0].unmarshal(fields...)
+			opts, texts = texts[0].templateOptions, texts[1:]
+		} else {
+			msg, err = regexps[0].unmarshal(text)
+			opts, regexps = regexps[0].templateOptions, regexps[1:]
 		}
+		opts.stats.update(err)
+		if err == nil {
+			msg.Template = opts.id
+			return complete(msg, source), nil
+		}
+		if err == ErrNotMatch {
+			continue
+		}
+		setTemplate(err, opts.id)
+		if failed == nil {
+			failed = err
+		}
+		if !opts.next {
+			break
+		}
+	}
+	if failed != nil {
+		log.Warnf("parse err - msg %s - %v", text, failed)
+		return nil, failed
 	}
 
-	return nil, fmt.Errorf("parse err - msg \"%s\" has unknown format ", text)
+	return nil, &ErrUnknownFormat{Message: fmt.Sprintf("parse err - msg \"%s\" has unknown format ", text)}
 }
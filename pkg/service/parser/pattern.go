@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"regexp"
 	"strconv"
@@ -14,7 +15,8 @@ import (
 // ErrDataParse - вспомогательный тип данных
 // для проблем обработки входящего сообщения.
 type ErrDataParse struct {
-	Message string
+	Message  string
+	Template string // идентификатор шаблона, совпавшего с сообщением
 }
 
 // Error - реализация итерфейса Error.
@@ -22,6 +24,23 @@ func (e *ErrDataParse) Error() string {
 	return e.Message
 }
 
+// setTemplate - дополнить ошибку обработки данных идентификатором шаблона.
+func setTemplate(err error, id string) {
+	if e, ok := err.(*ErrDataParse); ok && len(e.Template) == 0 {
+		e.Template = id
+	}
+}
+
+// ErrUnknownFormat - сообщение не совпадает ни с одним шаблоном обработчика.
+type ErrUnknownFormat struct {
+	Message string
+}
+
+// Error - реализация итерфейса Error.
+func (e *ErrUnknownFormat) Error() string {
+	return e.Message
+}
+
 var (
 	// ErrNotMatch - указанный текст не совпадает с текущим шаблоном.
 	ErrNotMatch = errors.New("not match")
@@ -29,34 +48,51 @@ var (
 	// вспомательное регулярное выражение для обработки текстовых полей.
 	digitsOnly = regexp.MustCompile("[0-9]+")
 
-	// Вспомогательные значения типа события шаблона.
-	eventKeyword = map[string]pb.EventType{
-		"ignore":     pb.EventType_Unknown,
-		"link_up":    pb.EventType_PortUp,
-		"link_down":  pb.EventType_PortDown,
-		"loopdetect": pb.EventType_PortLoopDetect,
+	// speedValue - значение скорости порта с необязательным префиксом единиц измерения.
+	speedValue = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s?([kmgt]?)`)
+	// speedUnit - множители префиксов единиц измерения скорости (к Мбит/с).
+	speedUnit = map[string]float64{
+		"":  1,
+		"k": 0.001,
+		"m": 1,
+		"g": 1000,
+		"t": 1000000,
+	}
+	// speedUnknown - значения неизвестной скорости порта.
+	speedUnknown = []string{"unknown", "n/a", "-"}
+	// speedKeyword - варианты скорости порта по значению в Мбит/с.
+	speedKeyword = map[uint64]pb.PortSpeed{
+		10:     pb.PortSpeed_Speed10Mb,
+		100:    pb.PortSpeed_Speed100Mb,
+		1000:   pb.PortSpeed_Speed1Gb,
+		2500:   pb.PortSpeed_Speed2500Mb,
+		5000:   pb.PortSpeed_Speed5Gb,
+		10000:  pb.PortSpeed_Speed10Gb,
+		25000:  pb.PortSpeed_Speed25Gb,
+		40000:  pb.PortSpeed_Speed40Gb,
+		50000:  pb.PortSpeed_Speed50Gb,
+		100000: pb.PortSpeed_Speed100Gb,
+		200000: pb.PortSpeed_Speed200Gb,
+		400000: pb.PortSpeed_Speed400Gb,
 	}
 )
 
 // textPattern - шаблон обработки текстовых сообщений.
 type textPattern struct {
-	eventType pb.EventType
+	eventType *eventType
 	fields    []*textField
+	templateOptions
 }
 
 // newTextPattern - создать новый экземпляр обработчика на базе шаблона.
-func newTextPattern(event, text string) (*textPattern, error) {
+func newTextPattern(event *eventType, text string, opts *fieldOptions) (*textPattern, error) {
 	p := &textPattern{
-		fields: make([]*textField, 0),
-	}
-	if t, exist := eventKeyword[event]; exist {
-		p.eventType = t
-	} else {
-		return nil, fmt.Errorf("new text pattern - unknown event type - %s", event)
+		eventType: event,
+		fields:    make([]*textField, 0),
 	}
 	fields := strings.Fields(text)
 	for _, v := range fields {
-		p.fields = append(p.fields, newTextField(v))
+		p.fields = append(p.fields, newTextField(v, opts))
 	}
 
 	return p, nil
@@ -69,47 +105,74 @@ func (p *textPattern) unmarshal(recv ...string) (*pb.Event, error) {
 	if len(recv) != len(p.fields) {
 		return nil, ErrNotMatch
 	}
-	result := &pb.Event{Type: p.eventType}
+	result := p.eventType.newEvent()
 	for k, f := range p.fields {
-		switch f.match(recv[k]) {
-		case -1:
+		if f.match(recv[k]) == -1 {
 			return nil, ErrNotMatch
-		case deviceAddr:
-			{
-				addr, err := parseDeviceAddr(recv[k])
-				if err != nil {
-					return nil, &ErrDataParse{Message: fmt.Sprintf("device address parse err - %v", err)}
-				}
-				result.Host = addr
+		}
+		if err := setField(result, f, recv[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// setField - преобразовать значение поля шаблона и заполнить им событие.
+// Возвращает ErrDataParse - если значение имеет неверный формат.
+func setField(result *pb.Event, f *textField, text string) error {
+	switch f.dataType {
+	case deviceAddr:
+		{
+			addr, err := parseDeviceAddr(text)
+			if err != nil {
+				return &ErrDataParse{Message: fmt.Sprintf("device address parse err - %v", err)}
 			}
-		case devicePort:
-			{
-				port, err := parseDevicePort(recv[k])
-				if err != nil {
-					return nil, &ErrDataParse{Message: fmt.Sprintf("device port parse err - %v", err)}
-				}
-				result.Port = port
+			result.Host = addr
+		}
+	case devicePort:
+		{
+			port, err := parseDevicePort(text)
+			if err != nil {
+				return &ErrDataParse{Message: fmt.Sprintf("device port parse err - %v", err)}
 			}
-		case portSpeed:
-			{
-				speed, err := parsePortSpeed(recv[k])
-				if err != nil {
-					return nil, &ErrDataParse{Message: fmt.Sprintf("device port speed parse err - %v", err)}
-				}
-				result.Speed = speed
+			result.Port = port
+			result.Interface, result.InterfaceID = f.ifRules.normalize(text)
+		}
+	case portSpeed:
+		{
+			speed, mbps, err := parsePortSpeed(text)
+			if err != nil {
+				return &ErrDataParse{Message: fmt.Sprintf("device port speed parse err - %v", err)}
+			}
+			result.Speed = speed
+			result.SpeedMbps = mbps
+		}
+	case portDuplex:
+		{
+			dupx, err := parsePortDuplex(text)
+			if err != nil {
+				return &ErrDataParse{Message: fmt.Sprintf("device port duplex parse err - %v", err)}
 			}
-		case portDuplex:
-			{
-				dupx, err := parsePortDuplex(recv[k])
+			result.Duplex = dupx
+		}
+	case attribute:
+		{
+			if f.convert != nil {
+				value, err := f.convert(text)
 				if err != nil {
-					return nil, &ErrDataParse{Message: fmt.Sprintf("device port duplex parse err - %v", err)}
+					return &ErrDataParse{Message: fmt.Sprintf("field %s parse err - %v", f.name, err)}
 				}
-				result.Duplex = dupx
+				text = value
+			}
+			if result.Attributes == nil {
+				result.Attributes = make(map[string]string)
 			}
+			result.Attributes[f.name] = text
 		}
 	}
 
-	return result, nil
+	return nil
 }
 
 // parseDeviceAddr - вспомогательная функция обработки данных.
@@ -135,21 +198,34 @@ func parseDevicePort(s string) (uint32, error) {
 }
 
 // parsePortSpeed - вспомогательная функция обработки данных.
-func parsePortSpeed(s string) (pb.PortSpeed, error) {
-	nums := digitsOnly.FindAllString(s, -1)
-	if len(nums) < 1 {
-		return 0, errors.New("unknown port speed format")
+// Возвращает вариант скорости и скорость в Мбит/с. Скорость указывается числом
+// с необязательными единицами измерения (100, 100mb, 10G, 25Gb/s, 100000Mbps),
+// без единиц - в Мбит/с. Нестандартная скорость возвращается как UnknownSpeed
+// (с заполненным значением в Мбит/с), "auto" - как SpeedAuto.
+func parsePortSpeed(s string) (pb.PortSpeed, uint64, error) {
+	text := strings.ToLower(s)
+	for _, v := range speedUnknown {
+		if text == v {
+			return pb.PortSpeed_UnknownSpeed, 0, nil
+		}
+	}
+	if strings.Contains(text, "auto") {
+		return pb.PortSpeed_SpeedAuto, 0, nil
+	}
+	args := speedValue.FindStringSubmatch(text)
+	if args == nil {
+		return pb.PortSpeed_UnknownSpeed, 0, errors.New("unknown port speed format")
+	}
+	value, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return pb.PortSpeed_UnknownSpeed, 0, fmt.Errorf("parsing port speed err - %v", err)
 	}
-	switch nums[0] {
-	case "10":
-		return pb.PortSpeed_Speed10Mb, nil
-	case "100":
-		return pb.PortSpeed_Speed100Mb, nil
-	case "1000":
-		return pb.PortSpeed_Speed1Gb, nil
+	mbps := uint64(math.Round(value * speedUnit[args[2]]))
+	if speed, exist := speedKeyword[mbps]; exist {
+		return speed, mbps, nil
 	}
 
-	return pb.PortSpeed_UnknownSpeed, errors.New("unknown port speed format")
+	return pb.PortSpeed_UnknownSpeed, mbps, nil
 }
 
 // parsePortDuplex - вспомогательная функция обработки данных.
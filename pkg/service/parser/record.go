@@ -0,0 +1,277 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ceeCookie - признак структурированного сообщения (CEE) перед JSON.
+	ceeCookie = "@cee:"
+)
+
+// newJSONParser - создать обработчик сообщений в формате JSON.
+// Вложенные объекты доступны в шаблонах по составным ключам ("link.state").
+func newJSONParser(templates []string, opts Options) (Parser, error) {
+	return newRecordParser(templates, opts, decodeJSON)
+}
+
+// newKVParser - создать обработчик сообщений в формате key=value
+// (значения с пробелами заключаются в двойные кавычки, слова без "=" пропускаются).
+func newKVParser(templates []string, opts Options) (Parser, error) {
+	return newRecordParser(templates, opts, decodeKV)
+}
+
+// recordParser - обработчик структурированных сообщений (JSON, key=value):
+// сообщение преобразуется в набор полей "ключ - значение", который сверяется
+// с шаблонами вида "тип ~ ключ=значение ключ=$поле$ ..." по приоритету и в порядке их указания.
+type recordParser struct {
+	decode    func(string) (map[string]string, error)
+	patterns  []*recordPattern
+	templates []templateOptions // параметры шаблонов в порядке указания
+}
+
+// Stats - статистика использования шаблонов в порядке их указания.
+func (x *recordParser) Stats() []TemplateStats {
+	return snapshots(x.templates)
+}
+
+// newRecordParser - создать обработчик структурированных сообщений.
+func newRecordParser(templates []string, opts Options, decode func(string) (map[string]string, error)) (Parser, error) {
+	if len(templates) == 0 {
+		return nil, errors.New("no patterns for parser are provided")
+	}
+	s, err := newSchema(opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &recordParser{
+		decode:   decode,
+		patterns: make([]*recordPattern, 0, len(templates)),
+	}
+	ids := make(templateIDs)
+	for k, v := range templates {
+		args := strings.SplitN(v, patternTypeDelim, 2)
+		if len(args) != 2 {
+			return nil, errors.New("unknown pattern format")
+		}
+		name, topts, err := parseHeader(args[0], k)
+		if err == nil {
+			err = ids.assign(&topts, name, patternTypeDelim, args[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("new record pattern - %v", err)
+		}
+		event, err := lookupEventType(s.types, name)
+		if err != nil {
+			return nil, fmt.Errorf("new record pattern - %v", err)
+		}
+		pattern, err := newRecordPattern(event, args[1], s.fields)
+		if err != nil {
+			return nil, err
+		}
+		pattern.templateOptions = topts
+		result.patterns = append(result.patterns, pattern)
+		result.templates = append(result.templates, topts)
+	}
+	sort.SliceStable(result.patterns, func(i, j int) bool {
+		return result.patterns[i].before(result.patterns[j].templateOptions)
+	})
+	log.Debugf("defined %d record parser patterns", len(templates))
+
+	return result, nil
+}
+
+// Parse - преобразовать сообщение в формат события GRPC.
+// Сообщение, которое не удалось разобрать как запись (не JSON, нет пар key=value),
+// имеет неизвестный формат - ошибка уровня обработки возвращается только для полей шаблона.
+func (x *recordParser) Parse(text, source string) (*pb.Event, error) {
+	rec, err := x.decode(text)
+	if err != nil {
+		return nil, &ErrUnknownFormat{Message: fmt.Sprintf("parse err - msg \"%s\" has unknown format - %v", text, err)}
+	}
+	var failed error
+	for _, pattern := range x.patterns {
+		msg, err := pattern.unmarshal(rec)
+		pattern.stats.update(err)
+		if err == nil {
+			msg.Template = pattern.id
+			return complete(msg, source), nil
+		}
+		if err == ErrNotMatch {
+			continue
+		}
+		setTemplate(err, pattern.id)
+		if failed == nil {
+			failed = err
+		}
+		if !pattern.next {
+			break
+		}
+	}
+	if failed != nil {
+		log.Warnf("parse err - msg %s - %v", text, failed)
+		return nil, failed
+	}
+
+	return nil, &ErrUnknownFormat{Message: fmt.Sprintf("parse err - msg \"%s\" has unknown format ", text)}
+}
+
+// recordPattern - шаблон обработки структурированных сообщений:
+// условия (значения ключей) и соответствие ключей полям события.
+type recordPattern struct {
+	eventType *eventType
+	conds     map[string]string
+	fields    map[string]*textField
+	templateOptions
+}
+
+// newRecordPattern - создать новый экземпляр обработчика на базе шаблона.
+func newRecordPattern(event *eventType, text string, opts *fieldOptions) (*recordPattern, error) {
+	p := &recordPattern{
+		eventType: event,
+		conds:     make(map[string]string),
+		fields:    make(map[string]*textField),
+	}
+	for _, v := range strings.Fields(text) {
+		args := strings.SplitN(v, "=", 2)
+		if len(args) != 2 || len(args[0]) == 0 {
+			return nil, fmt.Errorf("new record pattern - term \"%s\" is not key=value", v)
+		}
+		if f := newTextField(args[1], opts); f.dataType != plainText {
+			p.fields[args[0]] = f
+		} else {
+			p.conds[args[0]] = args[1]
+		}
+	}
+
+	return p, nil
+}
+
+// unmarshal - преобразовать поля сообщения в формат grpc.
+// Возвращает ErrNotMatch - если сообщение не соответствует условиям шаблона
+// или не содержит ключей полей, или ошибку уровня обработки.
+func (p *recordPattern) unmarshal(rec map[string]string) (*pb.Event, error) {
+	for key, value := range p.conds {
+		if rec[key] != value {
+			return nil, ErrNotMatch
+		}
+	}
+	for key := range p.fields {
+		if _, exist := rec[key]; !exist {
+			return nil, ErrNotMatch
+		}
+	}
+	result := p.eventType.newEvent()
+	for key, f := range p.fields {
+		if err := setField(result, f, rec[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// decodeJSON - преобразовать JSON-объект в набор полей,
+// вложенные объекты и массивы - по составным ключам ("a.b", "a.0").
+func decodeJSON(text string) (map[string]string, error) {
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), ceeCookie))
+	d := json.NewDecoder(strings.NewReader(text))
+	d.UseNumber()
+	var obj map[string]interface{}
+	if err := d.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("json decode err - %v", err)
+	}
+	result := make(map[string]string)
+	flatten(result, "", obj)
+	return result, nil
+}
+
+// flatten - добавить значение JSON в набор полей по ключу prefix.
+func flatten(result map[string]string, prefix string, value interface{}) {
+	key := func(k string) string {
+		if len(prefix) == 0 {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			flatten(result, key(k), item)
+		}
+	case []interface{}:
+		for k, item := range v {
+			flatten(result, key(fmt.Sprint(k)), item)
+		}
+	case nil:
+		result[prefix] = ""
+	default:
+		result[prefix] = fmt.Sprint(v)
+	}
+}
+
+// decodeKV - преобразовать сообщение вида key=value key="value with spaces" в набор полей.
+func decodeKV(text string) (map[string]string, error) {
+	result := make(map[string]string)
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t")
+		end := strings.IndexAny(text, " \t=")
+		if end == 0 {
+			text = text[1:]
+			continue
+		}
+		if end < 0 || text[end] != '=' {
+			// Слово без значения - пропускается.
+			if end < 0 {
+				break
+			}
+			text = text[end:]
+			continue
+		}
+		key := text[:end]
+		text = text[end+1:]
+		value, rest, err := kvValue(text)
+		if err != nil {
+			return nil, fmt.Errorf("kv decode key %s err - %v", key, err)
+		}
+		result[key] = value
+		text = rest
+	}
+	if len(result) == 0 {
+		return nil, errors.New("kv decode err - no key=value pairs")
+	}
+	return result, nil
+}
+
+// kvValue - считать значение (до пробела или в двойных кавычках с экранированием "\").
+func kvValue(text string) (string, string, error) {
+	if !strings.HasPrefix(text, `"`) {
+		if end := strings.IndexAny(text, " \t"); end >= 0 {
+			return text[:end], text[end:], nil
+		}
+		return text, "", nil
+	}
+	buf := bytes.Buffer{}
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if i+1 < len(text) {
+				i++
+				buf.WriteByte(text[i])
+			}
+		case '"':
+			return buf.String(), text[i+1:], nil
+		default:
+			buf.WriteByte(text[i])
+		}
+	}
+	return "", "", errors.New("quoted value is not terminated")
+}
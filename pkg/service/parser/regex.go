@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+const (
+	// разделитель данных "тип сообщения"-"регулярное выражение"
+	patternRegexDelim = " ~re~ "
+)
+
+// regexPattern - шаблон обработки сообщений на основе регулярного выражения.
+// Именованные группы выражения (?P<device_port>...) сопоставляются полям события,
+// имена групп совпадают с ключевыми словами текстовых шаблонов (без символов "$"),
+// группы с другими именами сохраняются в Attributes события.
+type regexPattern struct {
+	eventType *eventType
+	expr      *regexp.Regexp
+	fields    []*textField // поле каждой группы выражения (nil - не используется)
+	templateOptions
+}
+
+// newRegexPattern - создать новый экземпляр обработчика на базе регулярного выражения.
+func newRegexPattern(event *eventType, expr string, opts *fieldOptions) (*regexPattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("new regex pattern - compile err - %v", err)
+	}
+	p := &regexPattern{
+		eventType: event,
+		expr:      re,
+		fields:    make([]*textField, len(re.SubexpNames())),
+	}
+	for k, name := range re.SubexpNames() {
+		if len(name) == 0 {
+			continue
+		}
+		if dataType, ok := fieldType(name); !ok {
+			p.fields[k] = newAttributeField(name, opts)
+		} else if dataType != plainText {
+			p.fields[k] = newDataField(dataType, opts)
+		}
+	}
+
+	return p, nil
+}
+
+// unmarshal - преобразовать текстовое сообщение в формат grpc.
+// Возвращает ErrNotMatch - если сообщение не совпадает с выражением,
+// или ошибку уровня обработки. Группы, не участвующие в совпадении, пропускаются.
+func (p *regexPattern) unmarshal(text string) (*pb.Event, error) {
+	match := p.expr.FindStringSubmatchIndex(text)
+	if match == nil {
+		return nil, ErrNotMatch
+	}
+	result := p.eventType.newEvent()
+	for k, f := range p.fields {
+		if f == nil || match[2*k] < 0 {
+			continue
+		}
+		if err := setField(result, f, text[match[2*k]:match[2*k+1]]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
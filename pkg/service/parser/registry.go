@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// DefaultKind - вид обработчика по умолчанию (текстовые шаблоны и регулярные выражения).
+	DefaultKind = "template"
+)
+
+// Factory - функция создания обработчика сообщений по набору шаблонов.
+type Factory func(templates []string, opts Options) (Parser, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+func init() {
+	Register(DefaultKind, NewParser)
+	Register("json", newJSONParser)
+	Register("kv", newKVParser)
+}
+
+// Register - зарегистрировать вид обработчика сообщений.
+// Повторная регистрация вида с тем же именем вызывает panic.
+func Register(kind string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("parser: register factory is nil")
+	}
+	if _, exist := registry[kind]; exist {
+		panic("parser: register called twice for kind " + kind)
+	}
+	registry[kind] = factory
+}
+
+// Kinds - вернуть список зарегистрированных видов обработчиков.
+func Kinds() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	result := make([]string, 0, len(registry))
+	for kind := range registry {
+		result = append(result, kind)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// New - создать обработчик сообщений заданного вида (пустое значение - DefaultKind).
+func New(kind string, templates []string, opts Options) (Parser, error) {
+	if len(kind) == 0 {
+		kind = DefaultKind
+	}
+	registryMu.RLock()
+	factory, exist := registry[kind]
+	registryMu.RUnlock()
+	if !exist {
+		return nil, fmt.Errorf("parser kind \"%s\" are unknown", kind)
+	}
+	return factory(templates, opts)
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+	"net"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+// Scope - набор шаблонов, применяемый только к сообщениям устройств из заданных сетей.
+type Scope struct {
+	Name   string       // имя набора шаблонов
+	Nets   []*net.IPNet // сети устройств (пусто - все устройства)
+	Parser Parser       // обработчик шаблонов набора
+}
+
+// contains - проверить, применяется ли набор к сообщениям отправителя addr.
+func (s *Scope) contains(addr net.IP) bool {
+	if len(s.Nets) == 0 {
+		return true
+	}
+	for _, nwk := range s.Nets {
+		if addr != nil && nwk.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedParser - обработчик сообщений с наборами шаблонов, ограниченными сетями устройств.
+type scopedParser struct {
+	scopes   []Scope
+	fallback Parser
+}
+
+// NewScoped - создать обработчик, сверяющий сообщение с наборами шаблонов, в сети которых
+// входит адрес отправителя, в порядке их указания, затем - с обработчиком fallback (nil - не используется).
+// Ошибка обработки данных совпавшего шаблона завершает обработку сообщения.
+func NewScoped(scopes []Scope, fallback Parser) Parser {
+	return &scopedParser{scopes: scopes, fallback: fallback}
+}
+
+// Parse - (реализация интерфейса Parser) - преобразовать сообщение в формат события GRPC.
+func (p *scopedParser) Parse(text, source string) (*pb.Event, error) {
+	addr := net.ParseIP(source)
+	for k := range p.scopes {
+		if !p.scopes[k].contains(addr) {
+			continue
+		}
+		event, err := p.scopes[k].Parser.Parse(text, source)
+		if _, unknown := err.(*ErrUnknownFormat); !unknown {
+			return event, err
+		}
+	}
+	if p.fallback != nil {
+		return p.fallback.Parse(text, source)
+	}
+	return nil, &ErrUnknownFormat{Message: fmt.Sprintf("parse err - msg \"%s\" has unknown format ", text)}
+}
+
+// Stats - (реализация интерфейса Parser) - статистика использования шаблонов
+// наборов (с именем набора) и обработчика fallback.
+func (p *scopedParser) Stats() []TemplateStats {
+	result := make([]TemplateStats, 0)
+	for _, s := range p.scopes {
+		for _, v := range s.Parser.Stats() {
+			v.Pack = s.Name
+			result = append(result, v)
+		}
+	}
+	if p.fallback != nil {
+		result = append(result, p.fallback.Stats()...)
+	}
+	return result
+}
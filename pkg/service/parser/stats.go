@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TemplateStats - статистика использования шаблона.
+// Misses учитывает только сообщения, дошедшие до сверки с шаблоном: текстовые шаблоны
+// предварительно отбираются по словам сообщения (Options.Linear - по количеству слов),
+// регулярные выражения - нет, поэтому значения Misses разных шаблонов и режимов отбора несопоставимы.
+type TemplateStats struct {
+	ID        string    // идентификатор шаблона
+	Template  string    // текст шаблона
+	Pack      string    // набор шаблонов (пусто - шаблоны конфигурации)
+	Hits      uint64    // сообщений, преобразованных в события по шаблону
+	Misses    uint64    // сообщений, отобранных для сверки с шаблоном и не совпавших
+	Errors    uint64    // ошибок обработки данных совпавших сообщений
+	LastMatch time.Time // время последнего преобразования сообщения (нулевое - не было)
+}
+
+// templateStats - счетчики использования шаблона.
+type templateStats struct {
+	// Счетчики (доступ - только через sync/atomic)
+	hits      uint64
+	misses    uint64
+	errors    uint64
+	lastMatch int64
+
+	text string
+}
+
+// update - учесть результат сверки сообщения с шаблоном.
+func (s *templateStats) update(err error) {
+	switch err {
+	case nil:
+		atomic.AddUint64(&s.hits, 1)
+		atomic.StoreInt64(&s.lastMatch, time.Now().UnixNano())
+	case ErrNotMatch:
+		atomic.AddUint64(&s.misses, 1)
+	default:
+		atomic.AddUint64(&s.errors, 1)
+	}
+}
+
+// snapshot - текущее состояние счетчиков шаблона.
+func (t templateOptions) snapshot() TemplateStats {
+	result := TemplateStats{
+		ID:       t.id,
+		Template: t.stats.text,
+		Hits:     atomic.LoadUint64(&t.stats.hits),
+		Misses:   atomic.LoadUint64(&t.stats.misses),
+		Errors:   atomic.LoadUint64(&t.stats.errors),
+	}
+	if last := atomic.LoadInt64(&t.stats.lastMatch); last != 0 {
+		result.LastMatch = time.Unix(0, last)
+	}
+	return result
+}
+
+// snapshots - текущее состояние счетчиков шаблонов.
+func snapshots(templates []templateOptions) []TemplateStats {
+	result := make([]TemplateStats, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, t.snapshot())
+	}
+	return result
+}
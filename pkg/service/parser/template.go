@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// onErrorStop - при ошибке обработки данных прекратить разбор сообщения (по умолчанию).
+	onErrorStop = "stop"
+	// onErrorNext - при ошибке обработки данных продолжить сверку со следующими шаблонами.
+	onErrorNext = "next"
+)
+
+var (
+	// templateHeader - заголовок шаблона: тип события и параметры в квадратных скобках
+	// ("link_down [priority=10 on_error=next]").
+	templateHeader = regexp.MustCompile(`^\s*([^\s\[\]]+)\s*(?:\[([^\[\]]*)\])?\s*$`)
+	// templateName - допустимый идентификатор шаблона.
+	templateName = regexp.MustCompile(`^[\w.-]+$`)
+)
+
+// templateOptions - параметры шаблона, определяющие порядок сверки сообщения с шаблонами.
+// Шаблоны сверяются по убыванию приоритета, при равном приоритете - текстовые раньше
+// регулярных выражений, далее - в порядке указания в конфигурации.
+type templateOptions struct {
+	id       string // идентификатор шаблона
+	order    int    // порядковый номер шаблона в конфигурации
+	priority int    // приоритет (по умолчанию - 0)
+	next     bool   // при ошибке обработки данных продолжить сверку со следующими шаблонами
+
+	stats *templateStats // статистика использования шаблона
+}
+
+// before - признак сверки шаблона раньше шаблона того же вида o.
+func (t templateOptions) before(o templateOptions) bool {
+	if t.priority != o.priority {
+		return t.priority > o.priority
+	}
+	return t.order < o.order
+}
+
+// parseHeader - разобрать заголовок шаблона, вернуть имя типа события и параметры шаблона.
+func parseHeader(text string, order int) (string, templateOptions, error) {
+	result := templateOptions{order: order}
+	match := templateHeader.FindStringSubmatch(text)
+	if match == nil {
+		return "", result, fmt.Errorf("template header \"%s\" are invalid", text)
+	}
+	for _, v := range strings.Fields(match[2]) {
+		args := strings.SplitN(v, "=", 2)
+		if len(args) != 2 {
+			return "", result, fmt.Errorf("template option \"%s\" is not key=value", v)
+		}
+		switch args[0] {
+		case "priority":
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return "", result, fmt.Errorf("template priority \"%s\" are invalid", args[1])
+			}
+			result.priority = n
+		case "id":
+			if !templateName.MatchString(args[1]) {
+				return "", result, fmt.Errorf("template id \"%s\" are invalid", args[1])
+			}
+			result.id = args[1]
+		case "on_error":
+			if args[1] != onErrorStop && args[1] != onErrorNext {
+				return "", result, fmt.Errorf("template on_error \"%s\" are unknown", args[1])
+			}
+			result.next = args[1] == onErrorNext
+		default:
+			return "", result, fmt.Errorf("template option \"%s\" are unknown", args[0])
+		}
+	}
+	return match[1], result, nil
+}
+
+// templateIDs - назначенные идентификаторы шаблонов обработчика.
+type templateIDs map[string]bool
+
+// assign - назначить шаблону идентификатор и создать счетчики статистики использования: указанный в параметрах (id=...), либо по умолчанию -
+// контрольную сумму типа события и тела шаблона (не зависит от порядка шаблонов и прочих параметров).
+// Совпадающие шаблоны получают идентификаторы с номером (-2, -3 ...).
+func (ids templateIDs) assign(t *templateOptions, name, delim, body string) error {
+	t.stats = &templateStats{text: name + delim + body}
+	if len(t.id) != 0 {
+		if ids[t.id] {
+			return fmt.Errorf("template id \"%s\" are duplicated", t.id)
+		}
+		ids[t.id] = true
+		return nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name + delim + strings.TrimSpace(body)))
+	id := fmt.Sprintf("%08x", h.Sum32())
+	t.id = id
+	for n := 2; ids[t.id]; n++ {
+		t.id = fmt.Sprintf("%s-%d", id, n)
+	}
+	ids[t.id] = true
+	return nil
+}
+
+// sortTextPatterns - упорядочить текстовые шаблоны по приоритету и порядку указания.
+func sortTextPatterns(arr []*textPattern) {
+	sort.SliceStable(arr, func(i, j int) bool {
+		return arr[i].before(arr[j].templateOptions)
+	})
+}
+
+// shadows - признак того, что шаблон p перекрывает шаблон o: сверяется раньше,
+// не передает сообщение следующим шаблонам при ошибке данных и совпадает с любым
+// сообщением, совпадающим с o (все слова p - поля данных или те же слова).
+func (p *textPattern) shadows(o *textPattern) bool {
+	if p.next || !p.before(o.templateOptions) || len(p.fields) != len(o.fields) {
+		return false
+	}
+	for k, f := range p.fields {
+		if f.dataType == plainText && (o.fields[k].dataType != plainText || o.fields[k].text != f.text) {
+			return false
+		}
+	}
+	return true
+}
+
+// shadows - признак того, что регулярное выражение p перекрывает выражение o:
+// сверяется раньше, не передает сообщение следующим шаблонам при ошибке данных
+// и совпадает с o (после подстановки именованных выражений).
+func (p *regexPattern) shadows(o *regexPattern) bool {
+	return !p.next && p.before(o.templateOptions) && p.expr.String() == o.expr.String()
+}
+
+// warnShadowed - сообщить о шаблонах, которые никогда не совпадут с сообщением,
+// так как перекрыты другими шаблонами: текстовых - шаблонами с теми же или более общими словами,
+// регулярных выражений - тем же выражением.
+func warnShadowed(texts []*textPattern, regexps []*regexPattern, templates []string) {
+	warn := func(o, p templateOptions) {
+		log.Warnf("template #%d \"%s\" is shadowed by template #%d \"%s\"",
+			o.order+1, templates[o.order], p.order+1, templates[p.order])
+	}
+	for _, o := range texts {
+		for _, p := range texts {
+			if p != o && p.shadows(o) {
+				warn(o.templateOptions, p.templateOptions)
+				break
+			}
+		}
+	}
+	for _, o := range regexps {
+		for _, p := range regexps {
+			if p != o && p.shadows(o) {
+				warn(o.templateOptions, p.templateOptions)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+// trieNode - узел префиксного дерева текстовых шаблонов.
+// Каждый уровень дерева соответствует слову сообщения: переход по совпадающему
+// слову шаблона (literal), либо по полю данных (wildcard). Шаблон размещается
+// в узле, соответствующем его последнему слову.
+type trieNode struct {
+	literal  map[string]*trieNode
+	wildcard *trieNode
+	patterns []*textPattern
+}
+
+// newTrieNode - создать новый узел дерева.
+func newTrieNode() *trieNode {
+	return &trieNode{
+		literal: make(map[string]*trieNode),
+	}
+}
+
+// insert - добавить шаблон в дерево.
+func (n *trieNode) insert(p *textPattern) {
+	node := n
+	for _, f := range p.fields {
+		if f.dataType != plainText {
+			if node.wildcard == nil {
+				node.wildcard = newTrieNode()
+			}
+			node = node.wildcard
+			continue
+		}
+		next, exist := node.literal[f.text]
+		if !exist {
+			next = newTrieNode()
+			node.literal[f.text] = next
+		}
+		node = next
+	}
+	node.patterns = append(node.patterns, p)
+}
+
+// lookup - найти шаблоны, слова которых совпадают со словами сообщения,
+// в порядке сверки (по приоритету и порядку указания в конфигурации).
+func (n *trieNode) lookup(words []string) []*textPattern {
+	result := n.collect(words, nil)
+	if len(result) > 1 {
+		sortTextPatterns(result)
+	}
+	return result
+}
+
+// collect - обход дерева с накоплением найденных шаблонов.
+func (n *trieNode) collect(words []string, result []*textPattern) []*textPattern {
+	if len(words) == 0 {
+		return append(result, n.patterns...)
+	}
+	if next, exist := n.literal[words[0]]; exist {
+		result = next.collect(words[1:], result)
+	}
+	if n.wildcard != nil {
+		result = n.wildcard.collect(words[1:], result)
+	}
+	return result
+}
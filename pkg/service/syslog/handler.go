@@ -0,0 +1,326 @@
+package syslog
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultQueueSize - размер очереди сообщений обработчика по умолчанию.
+	defaultQueueSize = 1024
+	// overflowLogRate - периодичность записи в журнал о переполнении очереди (в сообщениях).
+	overflowLogRate = 1000
+	// stormTypeName, stormSeverity - имя типа и уровень важности (warning) события StormSuppressed.
+	stormTypeName = "storm_suppressed"
+	stormSeverity = 4
+)
+
+// Options - параметры обработки входящих сообщений, общие для всех типов Listener.
+type Options struct {
+	BufSize      int              // размер буфера входящих сообщений (максимальный размер сообщения)
+	IdleTimeout  time.Duration    // время бездействия tcp-соединения до закрытия (0 - без ограничения)
+	Location     *time.Location   // часовой пояс устройств для заголовков RFC 3164 (nil - локальный)
+	PreferSource bool             // адрес отправителя пакета имеет приоритет над адресом из сообщения
+	Workers      int              // количество потоков обработки сообщений (0 - по числу CPU)
+	QueueSize    int              // размер очереди сообщений каждого потока (0 - по умолчанию)
+	Sockets      int              // количество UDP-сокетов с SO_REUSEPORT (0, 1 - один сокет без SO_REUSEPORT)
+	Batch        int              // количество UDP-сообщений, считываемых за один вызов (0, 1 - по одному)
+	ReadBuffer   int              // размер приемного буфера UDP-сокета в ядре (0 - по умолчанию ОС)
+	RateLimit    RateLimit        // ограничение интенсивности приема сообщений
+	Failed       FailFunc         // получатель сообщений, которые не удалось преобразовать в события (nil - не используется)
+	Now          func() time.Time // источник текущего времени для определения года заголовков RFC 3164 (nil - time.Now)
+}
+
+// FailFunc - получатель сообщений, которые не удалось преобразовать в события:
+// текст сообщения (без заголовка syslog), адрес отправителя и ошибка обработчика шаблонов.
+type FailFunc func(text, source string, err error)
+
+// Counters - состояние счетчиков Listener.
+type Counters struct {
+	Recv          uint64 // получено сообщений
+	Parsed        uint64 // сообщений, успешно преобразованных в события
+	Dropped       uint64 // сообщений, отброшенных из-за переполнения очереди
+	KernelDropped uint64 // сообщений, отброшенных ядром ОС (переполнение буфера сокета)
+	Limited       uint64 // сообщений, отброшенных ограничением интенсивности
+}
+
+// newHandler - создать обработчик сообщений, общий для всех типов Listener.
+func newHandler(opts Options, parser parser.Parser) (*handler, error) {
+	if parser == nil {
+		return nil, fmt.Errorf("ptr to parser is nil")
+	}
+	if opts.BufSize < 1 {
+		return nil, fmt.Errorf("listener buf size are invalid")
+	}
+	if opts.Workers < 0 || opts.QueueSize < 0 {
+		return nil, fmt.Errorf("listener workers params are invalid")
+	}
+	if opts.Workers == 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.QueueSize == 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	lim, err := newLimiter(opts.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		bufSize:      opts.BufSize,
+		preferSource: opts.PreferSource,
+		parser:       parser,
+		failed:       opts.Failed,
+		decoder:      newDecoder(opts.Location, opts.Now),
+		limiter:      lim,
+		queues:       make([]chan *message, opts.Workers),
+		done:         make(chan struct{}),
+	}
+	h.buffers.New = func() interface{} {
+		buf := make([]byte, h.bufSize)
+		return &buf
+	}
+	for k := range h.queues {
+		h.queues[k] = make(chan *message, opts.QueueSize)
+	}
+
+	return h, nil
+}
+
+// message - полученное сообщение, ожидающее обработки.
+// Текст сообщения передается строкой (text), либо буфером из пула
+// обработчика (data, size) - буфер возвращается в пул после обработки.
+type message struct {
+	text   string
+	data   *[]byte
+	size   int
+	source string
+	peer   string
+}
+
+// handler - обработка полученных сообщений независимо от транспорта:
+// разбор заголовка, сверка с шаблонами и передача события в канал.
+// Сообщения обрабатываются фиксированным набором потоков, сообщения
+// одного отправителя всегда попадают в один поток (сохраняется порядок).
+type handler struct {
+	// Счетчики (доступ - только через sync/atomic)
+	recv    uint64
+	parsed  uint64
+	dropped uint64
+	limited uint64
+
+	bufSize      int
+	preferSource bool
+	parser       parser.Parser
+	failed       FailFunc
+	decoder      *decoder
+	limiter      *limiter
+	result       chan *pb.Event
+	queues       []chan *message
+	buffers      sync.Pool
+	done         chan struct{}
+	stopOnce     sync.Once
+}
+
+// start - запустить потоки обработки сообщений с передачей событий в канал ch.
+func (h *handler) start(ch chan *pb.Event) {
+	h.result = ch
+	for _, q := range h.queues {
+		go h.work(q)
+	}
+	if h.limiter != nil {
+		go h.report()
+	}
+}
+
+// stop - завершить работу потоков обработки сообщений.
+func (h *handler) stop() {
+	h.stopOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// work - основной цикл потока обработки сообщений.
+func (h *handler) work(queue chan *message) {
+	for {
+		select {
+		case <-h.done:
+			return
+		case msg := <-queue:
+			h.handle(msg)
+		}
+	}
+}
+
+// report - периодически сообщать подписчикам об отправителях,
+// сообщения которых отброшены ограничением интенсивности (событие StormSuppressed).
+func (h *handler) report() {
+	ticker := time.NewTicker(h.limiter.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+		}
+		for source, n := range h.limiter.flush() {
+			log.Warnf("storm from %s is suppressed - %d messages dropped", source, n)
+			event := &pb.Event{
+				Type:       pb.EventType_StormSuppressed,
+				TypeName:   stormTypeName,
+				Severity:   stormSeverity,
+				Host:       source,
+				Suppressed: n,
+			}
+			// Отправители сверх maxSources учитываются вместе - адрес устройства не указывается.
+			if source == SuppressedOther {
+				event.Host = ""
+			}
+			select {
+			case h.result <- event:
+			case <-h.done:
+				return
+			}
+		}
+	}
+}
+
+// getBuffer - получить буфер сообщения из пула.
+func (h *handler) getBuffer() *[]byte {
+	return h.buffers.Get().(*[]byte)
+}
+
+// release - вернуть буфер сообщения (если используется) в пул.
+func (h *handler) release(msg *message) {
+	if msg.data != nil {
+		h.buffers.Put(msg.data)
+		msg.data = nil
+	}
+}
+
+// messageText - получить текст сообщения, буфер сообщения возвращается в пул.
+func (h *handler) messageText(msg *message) string {
+	if msg.data != nil {
+		msg.text = string((*msg.data)[:msg.size])
+		h.release(msg)
+	}
+	return msg.text
+}
+
+// enqueue - поставить сообщение в очередь обработки.
+// Сообщения отправителей, превысивших ограничение интенсивности, отбрасываются.
+// Очередь выбирается по адресу отправителя. Если wait == false и очередь заполнена -
+// сообщение отбрасывается, иначе - ожидается освобождение места в очереди.
+func (h *handler) enqueue(msg *message, wait bool) {
+	atomic.AddUint64(&h.recv, 1)
+	if h.limiter != nil && !h.limiter.allow(msg.source) {
+		atomic.AddUint64(&h.limited, 1)
+		h.release(msg)
+		return
+	}
+	queue := h.queues[0]
+	if len(h.queues) > 1 {
+		hash := fnv.New32a()
+		hash.Write([]byte(msg.source))
+		queue = h.queues[hash.Sum32()%uint32(len(h.queues))]
+	}
+	if wait {
+		select {
+		case queue <- msg:
+		case <-h.done:
+			h.release(msg)
+		}
+		return
+	}
+	select {
+	case queue <- msg:
+	default:
+		h.release(msg)
+		if atomic.AddUint64(&h.dropped, 1)%overflowLogRate == 1 {
+			log.Warnf("listener queue overflow - %d messages dropped", atomic.LoadUint64(&h.dropped))
+		}
+	}
+}
+
+// handle - обработать полученное сообщение и направить его в канал.
+// Заголовок syslog (если распознан) отделяется от сообщения,
+// с шаблонами сверяется только текст сообщения.
+// Адрес отправителя пакета используется, если шаблон не содержит адреса
+// устройства, либо вместо адреса из сообщения - при PreferSource.
+func (h *handler) handle(msg *message) {
+	header, text, err := h.decoder.decode(h.messageText(msg))
+	if err != nil && err != errNoHeader {
+		log.Debugf("listener decode header err - %v", err)
+	}
+	event, err := h.parser.Parse(text, msg.source)
+	if err != nil {
+		if h.failed != nil {
+			h.failed(text, msg.source, err)
+		}
+		return
+	}
+	atomic.AddUint64(&h.parsed, 1)
+	if h.preferSource && len(msg.source) != 0 {
+		event.Host = msg.source
+	}
+	event.Header = header
+	event.Peer = msg.peer
+	select {
+	case h.result <- event:
+	case <-h.done:
+	}
+}
+
+// Counters - вернуть текущее состояние счетчиков.
+func (h *handler) Counters() Counters {
+	return Counters{
+		Recv:    atomic.LoadUint64(&h.recv),
+		Parsed:  atomic.LoadUint64(&h.parsed),
+		Dropped: atomic.LoadUint64(&h.dropped),
+		Limited: atomic.LoadUint64(&h.limited),
+	}
+}
+
+// Suppressed - вернуть количество сообщений, отброшенных ограничением
+// интенсивности, по отправителям (nil - если ограничение не задано).
+func (h *handler) Suppressed() map[string]uint64 {
+	if h.limiter == nil {
+		return nil
+	}
+	return h.limiter.totals()
+}
+
+// String - текстовое представление счетчиков (для отладки).
+func (c Counters) String() string {
+	return fmt.Sprintf("recv - %d, parsed - %d, dropped - %d, kernel dropped - %d, limited - %d",
+		c.Recv, c.Parsed, c.Dropped, c.KernelDropped, c.Limited)
+}
+
+// isClosed - проверить, что ошибка вызвана закрытием соединения.
+func isClosed(err error) bool {
+	netOpError, ok := err.(*net.OpError)
+	return ok && netOpError.Err.Error() == "use of closed network connection"
+}
+
+// sourceAddr - IP-адрес отправителя (без номера порта),
+// для локальных сокетов - localSource.
+func sourceAddr(addr net.Addr) string {
+	switch v := addr.(type) {
+	case *net.UDPAddr:
+		return v.IP.String()
+	case *net.TCPAddr:
+		return v.IP.String()
+	case *net.UnixAddr:
+		return localSource
+	}
+	return ""
+}
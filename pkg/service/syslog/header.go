@@ -0,0 +1,47 @@
+package syslog
+
+import (
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+// decoder - обработчик заголовков syslog-сообщений (RFC 5424, RFC 3164).
+type decoder struct {
+	loc *time.Location   // часовой пояс устройств (для заголовков RFC 3164)
+	now func() time.Time // источник текущего времени (для определения года)
+}
+
+// newDecoder - создать новый обработчик заголовков.
+// loc - часовой пояс, в котором устройства формируют время сообщений RFC 3164,
+// now - источник текущего времени для определения года (nil - time.Now).
+func newDecoder(loc *time.Location, now func() time.Time) *decoder {
+	if loc == nil {
+		loc = time.Local
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &decoder{
+		loc: loc,
+		now: now,
+	}
+}
+
+// DecodeHeader - отделить заголовок syslog (RFC 5424, RFC 3164) от текста сообщения так же,
+// как при приеме сообщений. Возвращает данные заголовка (nil - если заголовок не распознан)
+// и текст сообщения. loc - часовой пояс устройств для заголовков RFC 3164 (nil - локальный).
+func DecodeHeader(text string, loc *time.Location) (*pb.SyslogHeader, string) {
+	header, msg, _ := newDecoder(loc, nil).decode(text)
+	return header, msg
+}
+
+// decode - отделить заголовок syslog от текста сообщения.
+// Возвращает данные заголовка (nil - если заголовок не распознан) и текст сообщения.
+func (d *decoder) decode(text string) (*pb.SyslogHeader, string, error) {
+	header, msg, err := decodeRFC5424(text)
+	if err == errNoHeader {
+		header, msg, err = decodeRFC3164(text, d.loc, d.now())
+	}
+	return header, msg, err
+}
@@ -1,100 +1,172 @@
 package syslog
 
 import (
-	"fmt"
+	"context"
 	"net"
+	"sync"
+	"sync/atomic"
 
 	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
 	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/ipv4"
 )
 
 // Listener - интерфейс приема входящих сообщений SYSLOG.
 type Listener interface {
-	// Listen - запустить основной цикл - занять UDP порт,
+	// Listen - запустить основной цикл - занять порт,
 	// в цикле обработать сообщения и передать их в канал retCh.
 	Listen(chan *pb.Event)
 
 	// Counters - вернуть текущее состояние счетчиков.
-	Counters() (int, int)
+	Counters() Counters
+
+	// Suppressed - вернуть количество сообщений, отброшенных ограничением
+	// интенсивности, по отправителям (для метрик сервиса).
+	Suppressed() map[string]uint64
 
 	// Close - завершить работу и закрыть соеднинение.
 	Close()
 }
 
-// NewListener - создать новый экземпляр Listener.
-func NewListener(addr string, bufSize int, parser parser.Parser) (Listener, error) {
-	if parser == nil {
-		return nil, fmt.Errorf("ptr to parser is nil")
-	}
-	if bufSize < 1 {
-		return nil, fmt.Errorf("listener buf size are invalid")
-	}
-	udpaddr, err := net.ResolveUDPAddr("udp", addr)
+// NewListener - создать новый экземпляр Listener для приема сообщений по UDP.
+// При opts.Sockets > 1 открывается несколько сокетов на одном адресе (SO_REUSEPORT),
+// ядро распределяет между ними пакеты разных отправителей. При opts.Batch > 1
+// сообщения считываются пачками (recvmmsg, только Linux) в буферы из пула обработчика.
+func NewListener(addr string, opts Options, parser parser.Parser) (Listener, error) {
+	h, err := newHandler(opts, parser)
 	if err != nil {
 		return nil, err
 	}
-	conn, err := net.ListenUDP("udp", udpaddr)
-	if err != nil {
-		return nil, err
+	sockets := opts.Sockets
+	if sockets < 1 {
+		sockets = 1
+	}
+	lc := net.ListenConfig{Control: udpControl(sockets > 1)}
+	l := &listener{
+		handler: h,
+		batch:   opts.Batch,
+		conns:   make([]*net.UDPConn, 0, sockets),
+		drops:   make([]uint64, sockets),
 	}
-	log.Infof("listen syslog messages on address %s", addr)
+	for k := 0; k < sockets; k++ {
+		conn, err := lc.ListenPacket(context.Background(), "udp", addr)
+		if err == nil && opts.ReadBuffer > 0 {
+			err = conn.(*net.UDPConn).SetReadBuffer(opts.ReadBuffer)
+		}
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l.conns = append(l.conns, conn.(*net.UDPConn))
+	}
+	log.Infof("listen syslog messages on address %s (sockets - %d)", addr, sockets)
 
-	return &listener{
-		bufSize: bufSize,
-		parser:  parser,
-		conn:    conn,
-	}, nil
+	return l, nil
 }
 
-// listener - реализация интерфейса Listener.
+// listener - реализация интерфейса Listener (UDP).
 type listener struct {
-	bufSize int
-	parser  parser.Parser
-	conn    *net.UDPConn
-	done    bool
-	result  chan *pb.Event
-
-	// Счетчики для отладки
-	recv   int
-	parsed int
+	*handler
+	batch int
+	conns []*net.UDPConn
+	drops []uint64 // счетчики ядра ОС для каждого сокета (доступ через sync/atomic)
 }
 
 // Listen - запустить основной цикл - занять UDP порт,
 // в цикле обработать сообщения и передать их в канал retCh.
+// Каждый сокет считывается в отдельной горутине.
 func (l *listener) Listen(ch chan *pb.Event) {
-	l.result = ch
+	l.start(ch)
+	wg := sync.WaitGroup{}
+	wg.Add(len(l.conns))
+	for k := range l.conns {
+		go func(k int) {
+			defer wg.Done()
+			if l.batch > 1 && batchSupported {
+				l.readBatch(k)
+			} else {
+				l.read(k)
+			}
+		}(k)
+	}
+	wg.Wait()
+}
+
+// read - считывать сообщения из сокета по одному.
+func (l *listener) read(k int) {
 	buf := make([]byte, l.bufSize)
+	oob := make([]byte, oobSize)
 	for {
-		n, _, err := l.conn.ReadFromUDP(buf)
+		n, oobn, _, src, err := l.conns[k].ReadMsgUDP(buf, oob)
 		if err != nil {
-			netOpError, ok := err.(*net.OpError)
-			if ok && netOpError.Err.Error() == "use of closed network connection" {
+			if isClosed(err) {
 				return
 			}
 			log.Debugf("listener recv err - %v", err)
 			continue
 		}
-		go l.handle(string(buf[:n]))
+		l.updateDrops(k, oob[:oobn])
+		l.enqueue(&message{text: string(buf[:n]), source: src.IP.String()}, false)
 	}
 }
 
-// handle - обработать полученное сообщение и направить его в канал.
-func (l *listener) handle(message string) {
-	l.recv++
-	if event, err := l.parser.Parse(message); err == nil {
-		l.parsed++
-		l.result <- event
+// readBatch - считывать сообщения из сокета пачками, каждое сообщение
+// считывается в буфер из пула и передается в очередь без копирования.
+func (l *listener) readBatch(k int) {
+	pc := ipv4.NewPacketConn(l.conns[k])
+	msgs := make([]ipv4.Message, l.batch)
+	bufs := make([]*[]byte, l.batch)
+	for i := range msgs {
+		bufs[i] = l.getBuffer()
+		msgs[i].Buffers = [][]byte{*bufs[i]}
+		msgs[i].OOB = make([]byte, oobSize)
+	}
+	defer func() {
+		for _, buf := range bufs {
+			l.release(&message{data: buf})
+		}
+	}()
+	for {
+		n, err := pc.ReadBatch(msgs, 0)
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			log.Debugf("listener recv err - %v", err)
+			continue
+		}
+		for i := 0; i < n; i++ {
+			l.updateDrops(k, msgs[i].OOB[:msgs[i].NN])
+			l.enqueue(&message{data: bufs[i], size: msgs[i].N, source: sourceAddr(msgs[i].Addr)}, false)
+			bufs[i] = l.getBuffer()
+			msgs[i].Buffers[0] = *bufs[i]
+		}
+	}
+}
+
+// updateDrops - обновить счетчик сообщений, отброшенных ядром ОС,
+// по данным управляющего сообщения сокета.
+func (l *listener) updateDrops(k int, oob []byte) {
+	if drops, ok := parseDrops(oob); ok {
+		atomic.StoreUint64(&l.drops[k], uint64(drops))
 	}
 }
 
 // Counters - вернуть текущее состояние счетчиков.
-func (l *listener) Counters() (int, int) {
-	return l.recv, l.parsed
+func (l *listener) Counters() Counters {
+	result := l.handler.Counters()
+	for k := range l.drops {
+		result.KernelDropped += atomic.LoadUint64(&l.drops[k])
+	}
+	return result
 }
 
-// Close - завершить работу и закрыть соеднинение.
+// Close - завершить работу и закрыть соеднинения.
 func (l *listener) Close() {
-	l.conn.Close()
-	log.Debugf("total: recv - %d, parsed - %d", l.recv, l.parsed)
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+	l.stop()
+	log.Debugf("total: %s", l.Counters())
 }
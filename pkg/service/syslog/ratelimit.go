@@ -0,0 +1,210 @@
+package syslog
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSubnetPrefix - длина префикса подсети IPv4 по умолчанию.
+	defaultSubnetPrefix = 24
+	// subnetPrefix6 - длина префикса подсети IPv6.
+	subnetPrefix6 = 64
+	// defaultStormInterval - период формирования событий StormSuppressed по умолчанию.
+	defaultStormInterval = 10 * time.Second
+	// maxSources - максимальное количество отправителей (подсетей) в состояниях ограничителя
+	// и счетчиках отброшенных сообщений, остальные отправители учитываются под общим ключом
+	// SuppressedOther (адрес отправителя UDP легко подделать - количество ключей должно быть ограничено).
+	maxSources = 1024
+
+	// SuppressedOther - общий ключ отправителей сверх maxSources.
+	SuppressedOther = "other"
+)
+
+// RateLimit - параметры ограничения интенсивности приема сообщений (token bucket).
+// Rate - допустимое количество сообщений в секунду (0 - без ограничения),
+// Burst - допустимый кратковременный всплеск (0 - равен Rate).
+type RateLimit struct {
+	SourceRate   float64       // ограничение для каждого отправителя
+	SourceBurst  int           // всплеск для каждого отправителя
+	SubnetRate   float64       // ограничение для каждой подсети отправителей
+	SubnetBurst  int           // всплеск для каждой подсети отправителей
+	SubnetPrefix int           // длина префикса подсети IPv4 (0 - 24), для IPv6 - всегда 64
+	Interval     time.Duration // период формирования событий StormSuppressed (0 - 10 секунд)
+}
+
+// bucket - состояние ограничителя (token bucket) для одного ключа.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill - пополнить запас на момент now и вернуть признак полного запаса.
+func (b *bucket) refill(now time.Time, rate float64, burst float64) bool {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	return b.tokens >= burst
+}
+
+// limiter - ограничитель интенсивности сообщений по отправителям и подсетям.
+// Учитывает количество отброшенных сообщений каждого отправителя.
+type limiter struct {
+	sourceRate  float64
+	sourceBurst float64
+	subnetRate  float64
+	subnetBurst float64
+	mask        net.IPMask
+	interval    time.Duration
+
+	mu         sync.Mutex
+	sources    map[string]*bucket
+	subnets    map[string]*bucket
+	suppressed map[string]uint64 // отброшено за текущий период
+	total      map[string]uint64 // отброшено за все время работы
+	now        func() time.Time
+}
+
+// newLimiter - создать ограничитель, nil - если ограничения не заданы.
+func newLimiter(cfg RateLimit) (*limiter, error) {
+	if cfg.SourceRate < 0 || cfg.SubnetRate < 0 || cfg.SourceBurst < 0 || cfg.SubnetBurst < 0 {
+		return nil, fmt.Errorf("rate limit params are invalid")
+	}
+	if cfg.SubnetPrefix < 0 || cfg.SubnetPrefix > 32 || cfg.Interval < 0 {
+		return nil, fmt.Errorf("rate limit subnet prefix or interval are invalid")
+	}
+	if cfg.SourceRate == 0 && cfg.SubnetRate == 0 {
+		return nil, nil
+	}
+	if cfg.SubnetPrefix == 0 {
+		cfg.SubnetPrefix = defaultSubnetPrefix
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultStormInterval
+	}
+
+	return &limiter{
+		sourceRate:  cfg.SourceRate,
+		sourceBurst: burstSize(cfg.SourceRate, cfg.SourceBurst),
+		subnetRate:  cfg.SubnetRate,
+		subnetBurst: burstSize(cfg.SubnetRate, cfg.SubnetBurst),
+		mask:        net.CIDRMask(cfg.SubnetPrefix, 32),
+		interval:    cfg.Interval,
+		sources:     make(map[string]*bucket),
+		subnets:     make(map[string]*bucket),
+		suppressed:  make(map[string]uint64),
+		total:       make(map[string]uint64),
+		now:         time.Now,
+	}, nil
+}
+
+// burstSize - размер всплеска, по умолчанию - равен ограничению (не менее одного сообщения).
+func burstSize(rate float64, burst int) float64 {
+	if burst > 0 {
+		return float64(burst)
+	}
+	return math.Max(1, math.Ceil(rate))
+}
+
+// allow - проверить, что сообщение отправителя source не превышает ограничений.
+// Отброшенные сообщения учитываются в счетчиках отправителя.
+func (l *limiter) allow(source string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	ok := true
+	var src, nwk *bucket
+	if l.sourceRate > 0 {
+		src = takeBucket(l.sources, source, now, l.sourceBurst)
+		src.refill(now, l.sourceRate, l.sourceBurst)
+		ok = src.tokens >= 1
+	}
+	if ok && l.subnetRate > 0 {
+		nwk = takeBucket(l.subnets, l.subnet(source), now, l.subnetBurst)
+		nwk.refill(now, l.subnetRate, l.subnetBurst)
+		ok = nwk.tokens >= 1
+	}
+	if !ok {
+		countSource(l.suppressed, source)
+		countSource(l.total, source)
+		return false
+	}
+	if src != nil {
+		src.tokens--
+	}
+	if nwk != nil {
+		nwk.tokens--
+	}
+	return true
+}
+
+// countSource - учесть отброшенное сообщение отправителя source
+// (при превышении maxSources отправителей - в счетчике SuppressedOther).
+func countSource(counts map[string]uint64, source string) {
+	if _, ok := counts[source]; !ok && len(counts) >= maxSources {
+		source = SuppressedOther
+	}
+	counts[source]++
+}
+
+// takeBucket - получить состояние ограничителя по ключу (новое - с полным запасом).
+// При превышении maxSources ключей новые ключи используют общее состояние SuppressedOther.
+func takeBucket(buckets map[string]*bucket, key string, now time.Time, burst float64) *bucket {
+	b, ok := buckets[key]
+	if !ok && len(buckets) >= maxSources {
+		key = SuppressedOther
+		b, ok = buckets[key]
+	}
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		buckets[key] = b
+	}
+	return b
+}
+
+// subnet - подсеть отправителя (для адресов, отличных от IP, - сам адрес).
+func (l *limiter) subnet(source string) string {
+	ip := net.ParseIP(source)
+	switch {
+	case ip == nil:
+		return source
+	case ip.To4() != nil:
+		return ip.Mask(l.mask).String()
+	}
+	return ip.Mask(net.CIDRMask(subnetPrefix6, 128)).String()
+}
+
+// flush - вернуть количество отброшенных за период сообщений по отправителям
+// и начать новый период. Состояния с полным запасом удаляются.
+func (l *limiter) flush() map[string]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for key, b := range l.sources {
+		if b.refill(now, l.sourceRate, l.sourceBurst) {
+			delete(l.sources, key)
+		}
+	}
+	for key, b := range l.subnets {
+		if b.refill(now, l.subnetRate, l.subnetBurst) {
+			delete(l.subnets, key)
+		}
+	}
+	result := l.suppressed
+	l.suppressed = make(map[string]uint64)
+	return result
+}
+
+// totals - количество отброшенных за все время сообщений по отправителям
+// (не более maxSources отправителей и счетчик SuppressedOther).
+func (l *limiter) totals() map[string]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make(map[string]uint64, len(l.total))
+	for source, n := range l.total {
+		result[source] = n
+	}
+	return result
+}
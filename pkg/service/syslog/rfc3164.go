@@ -0,0 +1,84 @@
+package syslog
+
+import (
+	"strings"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+const (
+	// rfc3164Stamp - формат TIMESTAMP заголовка RFC 3164 (без года и часового пояса).
+	rfc3164Stamp = "Jan _2 15:04:05"
+	// maxTagLen - максимальная длина TAG (RFC 3164, 4.1.3).
+	maxTagLen = 32
+	// futureSlack - допустимое "опережение" времени устройства,
+	// при превышении которого считается, что сообщение сформировано в прошлом году.
+	futureSlack = 24 * time.Hour
+)
+
+// decodeRFC3164 - разобрать заголовок сообщения формата RFC 3164 (BSD syslog)
+// "<PRI>Mmm dd hh:mm:ss HOSTNAME TAG: MSG".
+// Время интерпретируется в часовом поясе loc, год определяется по текущей дате now
+// (переход через новый год - сообщения "из будущего" относятся к предыдущему году,
+// январские сообщения, полученные в конце декабря, - к следующему).
+// Возвращает данные заголовка и текст сообщения без заголовка,
+// errNoHeader - если сообщение не соответствует формату.
+func decodeRFC3164(text string, loc *time.Location, now time.Time) (*pb.SyslogHeader, string, error) {
+	pri, rest, err := parsePriority(text)
+	if err != nil {
+		return nil, text, err
+	}
+	if len(rest) < len(rfc3164Stamp)+1 || rest[len(rfc3164Stamp)] != ' ' {
+		return nil, text, errNoHeader
+	}
+	ts, err := time.ParseInLocation(rfc3164Stamp, rest[:len(rfc3164Stamp)], loc)
+	if err != nil {
+		return nil, text, errNoHeader
+	}
+	rest = rest[len(rfc3164Stamp)+1:]
+	// Часть устройств дополняет время долями секунды.
+	if len(rest) > 1 && rest[0] == '.' {
+		if i := strings.IndexByte(rest, ' '); i > 0 {
+			if frac, err := time.ParseDuration("0" + rest[:i] + "s"); err == nil {
+				ts = ts.Add(frac)
+				rest = rest[i+1:]
+			}
+		}
+	}
+
+	header := newHeader(pri)
+	header.Timestamp = inferYear(ts, now.In(loc)).UnixNano()
+	header.Hostname, rest = nextToken(rest)
+	if len(header.Hostname) == 0 {
+		return nil, text, errNoHeader
+	}
+	if tag, msg := nextToken(rest); strings.HasSuffix(tag, ":") && len(tag) <= maxTagLen+1 {
+		header.AppName, header.ProcID = splitTag(strings.TrimSuffix(tag, ":"))
+		rest = msg
+	}
+
+	return header, rest, nil
+}
+
+// inferYear - дополнить время без года текущим годом (now), предыдущим - если результат
+// оказывается в будущем, либо следующим - если время устройства уже перешло через новый год.
+func inferYear(ts, now time.Time) time.Time {
+	years := now.Year() - ts.Year()
+	result := ts.AddDate(years, 0, 0)
+	if result.Sub(now) > futureSlack {
+		return ts.AddDate(years-1, 0, 0)
+	}
+	if next := ts.AddDate(years+1, 0, 0); next.Sub(now) <= futureSlack {
+		return next
+	}
+	return result
+}
+
+// splitTag - разделить TAG вида "name[pid]" на имя приложения и идентификатор процесса.
+func splitTag(tag string) (string, string) {
+	if i := strings.IndexByte(tag, '['); i > 0 && strings.HasSuffix(tag, "]") {
+		return tag[:i], tag[i+1 : len(tag)-1]
+	}
+	return tag, ""
+}
@@ -0,0 +1,151 @@
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+)
+
+const (
+	// nilValue - отсутствующее значение поля заголовка.
+	nilValue = "-"
+	// utf8BOM - метка порядка байт, допустимая в начале MSG (RFC 5424, 6.4).
+	utf8BOM = "\xef\xbb\xbf"
+	// maxPriority - максимальное значение PRI (23 * 8 + 7).
+	maxPriority = 191
+)
+
+var (
+	// errNoHeader - сообщение не содержит заголовка syslog ожидаемого формата.
+	errNoHeader = errors.New("no syslog header")
+)
+
+// parsePriority - извлечь значение PRI ("<N>") из начала сообщения.
+// Возвращает значение и оставшуюся часть сообщения.
+func parsePriority(text string) (uint32, string, error) {
+	if len(text) < 3 || text[0] != '<' {
+		return 0, text, errNoHeader
+	}
+	end := strings.IndexByte(text, '>')
+	if end < 2 || end > 4 {
+		return 0, text, errNoHeader
+	}
+	pri, err := strconv.ParseUint(text[1:end], 10, 8)
+	if err != nil || pri > maxPriority {
+		return 0, text, errNoHeader
+	}
+
+	return uint32(pri), text[end+1:], nil
+}
+
+// newHeader - создать заголовок события на основе значения PRI.
+func newHeader(pri uint32) *pb.SyslogHeader {
+	return &pb.SyslogHeader{
+		Priority: pri,
+		Facility: pri / 8,
+		Severity: pri % 8,
+	}
+}
+
+// decodeRFC5424 - разобрать заголовок сообщения формата RFC 5424
+// "<PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG".
+// Возвращает данные заголовка и текст сообщения (MSG) без заголовка,
+// errNoHeader - если сообщение не соответствует формату.
+func decodeRFC5424(text string) (*pb.SyslogHeader, string, error) {
+	pri, rest, err := parsePriority(text)
+	if err != nil {
+		return nil, text, err
+	}
+	// VERSION - 1-2 цифры, первая не может быть нулем.
+	version, rest := nextToken(rest)
+	if len(version) < 1 || len(version) > 2 || version[0] < '1' || version[0] > '9' {
+		return nil, text, errNoHeader
+	}
+	if _, err := strconv.Atoi(version); err != nil {
+		return nil, text, errNoHeader
+	}
+
+	fields := make([]string, 5)
+	for i := range fields {
+		if len(rest) == 0 {
+			return nil, text, errNoHeader
+		}
+		fields[i], rest = nextToken(rest)
+		if len(fields[i]) == 0 {
+			return nil, text, errNoHeader
+		}
+	}
+	header := newHeader(pri)
+	if fields[0] != nilValue {
+		ts, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, text, fmt.Errorf("rfc5424 timestamp parse err - %v", err)
+		}
+		header.Timestamp = ts.UnixNano()
+	}
+	header.Hostname = nilToEmpty(fields[1])
+	header.AppName = nilToEmpty(fields[2])
+	header.ProcID = nilToEmpty(fields[3])
+	header.MsgID = nilToEmpty(fields[4])
+
+	msg, err := skipStructuredData(rest)
+	if err != nil {
+		return nil, text, err
+	}
+	if len(msg) > 0 && msg[0] == ' ' {
+		msg = msg[1:]
+	}
+
+	return header, strings.TrimPrefix(msg, utf8BOM), nil
+}
+
+// skipStructuredData - пропустить блок STRUCTURED-DATA ("-" или набор
+// элементов "[id param="value" ...]") и вернуть оставшуюся часть сообщения.
+func skipStructuredData(text string) (string, error) {
+	if strings.HasPrefix(text, nilValue) {
+		return text[1:], nil
+	}
+	if len(text) == 0 || text[0] != '[' {
+		return text, errNoHeader
+	}
+	for len(text) > 0 && text[0] == '[' {
+		quoted := false
+		end := -1
+		for i := 1; i < len(text) && end < 0; i++ {
+			switch {
+			case text[i] == '\\' && quoted:
+				i++
+			case text[i] == '"':
+				quoted = !quoted
+			case text[i] == ']' && !quoted:
+				end = i
+			}
+		}
+		if end < 0 {
+			return text, errors.New("rfc5424 structured data is not terminated")
+		}
+		text = text[end+1:]
+	}
+
+	return text, nil
+}
+
+// nextToken - вернуть очередное поле заголовка (до пробела) и остаток строки.
+func nextToken(text string) (string, string) {
+	if i := strings.IndexByte(text, ' '); i >= 0 {
+		return text[:i], text[i+1:]
+	}
+	return text, ""
+}
+
+// nilToEmpty - заменить отсутствующее значение ("-") пустой строкой.
+func nilToEmpty(s string) string {
+	if s == nilValue {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,218 @@
+package syslog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxLenDigits - максимальное количество цифр MSG-LEN (octet counting).
+	maxLenDigits = 9
+)
+
+var (
+	// errFrameTooLarge - размер сообщения превышает размер буфера.
+	errFrameTooLarge = errors.New("message exceeds buf size")
+)
+
+// NewTCPListener - создать новый экземпляр Listener для приема сообщений по TCP (RFC 6587).
+// Поддерживаются оба способа разделения сообщений в потоке - octet counting
+// ("MSG-LEN SP SYSLOG-MSG") и non-transparent framing ("SYSLOG-MSG LF").
+// Соединение закрывается по истечении времени бездействия opts.IdleTimeout.
+func NewTCPListener(addr string, opts Options, parser parser.Parser) (Listener, error) {
+	h, err := newHandler(opts, parser)
+	if err != nil {
+		return nil, err
+	}
+	lsn, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("listen syslog messages on tcp address %s", addr)
+
+	return newTCPListener(h, lsn, opts.IdleTimeout), nil
+}
+
+// newTCPListener - создать обработчик потока сообщений на базе открытого порта.
+func newTCPListener(h *handler, lsn net.Listener, idleTimeout time.Duration) *tcpListener {
+	return &tcpListener{
+		handler:     h,
+		lsn:         lsn,
+		idleTimeout: idleTimeout,
+		connMu:      sync.Mutex{},
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// tcpListener - реализация интерфейса Listener (TCP, TLS).
+type tcpListener struct {
+	*handler
+	lsn         net.Listener
+	idleTimeout time.Duration
+	connMu      sync.Mutex
+	conns       map[net.Conn]struct{}
+}
+
+// Listen - запустить основной цикл - принимать входящие соединения,
+// каждое соединение обрабатывается в отдельной горутине.
+func (l *tcpListener) Listen(ch chan *pb.Event) {
+	l.start(ch)
+	for {
+		conn, err := l.lsn.Accept()
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			log.Debugf("listener accept err - %v", err)
+			continue
+		}
+		go l.serve(conn)
+	}
+}
+
+// serve - считать сообщения из соединения и передать их в очередь обработки.
+// При заполнении очереди чтение из соединения приостанавливается.
+func (l *tcpListener) serve(conn net.Conn) {
+	l.connMu.Lock()
+	l.conns[conn] = struct{}{}
+	l.connMu.Unlock()
+	log.Debugf("tcp connection from %s is accepted", conn.RemoteAddr())
+
+	defer func() {
+		l.connMu.Lock()
+		delete(l.conns, conn)
+		l.connMu.Unlock()
+		conn.Close()
+		log.Debugf("tcp connection from %s is closed", conn.RemoteAddr())
+	}()
+
+	source := sourceAddr(conn.RemoteAddr())
+	peer, err := handshake(conn, l.idleTimeout)
+	if err != nil {
+		log.Warnf("tls handshake with %s failed - %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	r := bufio.NewReaderSize(conn, l.bufSize)
+	for {
+		if l.idleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(l.idleTimeout))
+		}
+		msg, err := readFrame(r, l.bufSize)
+		if err == errFrameTooLarge {
+			log.Debugf("listener recv err - %s - %v", conn.RemoteAddr(), err)
+			continue
+		}
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				log.Debugf("tcp connection from %s is idle", conn.RemoteAddr())
+			} else if err != io.EOF && !isClosed(err) {
+				log.Debugf("listener recv err - %s - %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if len(msg) > 0 {
+			l.enqueue(&message{text: msg, source: source, peer: peer}, true)
+		}
+	}
+}
+
+// Close - завершить работу и закрыть все соеднинения.
+func (l *tcpListener) Close() {
+	l.lsn.Close()
+	l.connMu.Lock()
+	for conn := range l.conns {
+		conn.Close()
+	}
+	l.connMu.Unlock()
+	l.stop()
+	log.Debugf("total: %s", l.Counters())
+}
+
+// readFrame - считать из потока очередное сообщение (RFC 6587).
+// Если сообщение начинается с числа, за которым следует пробел - используется
+// octet counting, иначе сообщение считывается до символа LF.
+func readFrame(r *bufio.Reader, bufSize int) (string, error) {
+	prefix := make([]byte, 0, maxLenDigits+1)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(prefix) > 0 {
+				return string(prefix), nil
+			}
+			return "", err
+		}
+		if b == ' ' && len(prefix) > 0 && prefix[0] != '0' && len(prefix) <= maxLenDigits {
+			n, _ := strconv.Atoi(string(prefix))
+			return readOctets(r, n, bufSize)
+		}
+		if b == '\n' {
+			return trimCR(prefix), nil
+		}
+		prefix = append(prefix, b)
+		if b < '0' || b > '9' || len(prefix) > maxLenDigits {
+			break
+		}
+	}
+
+	return readLine(r, prefix, bufSize)
+}
+
+// readOctets - считать сообщение заданной длины.
+func readOctets(r *bufio.Reader, n, bufSize int) (string, error) {
+	if n > bufSize {
+		if _, err := io.CopyN(ioutil.Discard, r, int64(n)); err != nil {
+			return "", err
+		}
+		return "", errFrameTooLarge
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// readLine - считать сообщение до символа LF, начало сообщения передается в line.
+// Часть сообщения, превышающая размер буфера, отбрасывается.
+func readLine(r *bufio.Reader, line []byte, bufSize int) (string, error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line) < bufSize {
+			if len(line)+len(chunk) > bufSize {
+				chunk = chunk[:bufSize-len(line)]
+			}
+			line = append(line, chunk...)
+		}
+		switch err {
+		case nil:
+			return trimCR(line), nil
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			if len(line) > 0 {
+				return string(line), nil
+			}
+		}
+		return "", err
+	}
+}
+
+// trimCR - удалить завершающие символы конца строки.
+func trimCR(line []byte) string {
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+	return string(line)
+}
@@ -0,0 +1,75 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// handshakeTimeout - время ожидания завершения TLS handshake
+	// (если не задано время бездействия соединения).
+	handshakeTimeout = 30 * time.Second
+)
+
+// NewTLSListener - создать новый экземпляр Listener для приема сообщений по TLS (RFC 5425).
+// Разделение сообщений в потоке аналогично NewTCPListener. Если клиент предъявил
+// сертификат, прошедший проверку - его имя (CN) записывается в поле Peer каждого события.
+// Непроверенный сертификат (tls.RequireAnyClientCert) устройство не удостоверяет - Peer не заполняется
+// (конфигурация сервиса такой режим не допускает).
+// cfg - параметры TLS (сертификат сервиса, проверка сертификатов клиентов).
+func NewTLSListener(addr string, cfg *tls.Config, opts Options, parser parser.Parser) (Listener, error) {
+	if cfg == nil || len(cfg.Certificates) == 0 {
+		return nil, fmt.Errorf("tls certificate are not set")
+	}
+	h, err := newHandler(opts, parser)
+	if err != nil {
+		return nil, err
+	}
+	lsn, err := tls.Listen("tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("listen syslog messages on tls address %s", addr)
+
+	return newTCPListener(h, lsn, opts.IdleTimeout), nil
+}
+
+// handshake - для TLS-соединения выполнить handshake и вернуть имя клиента,
+// если его сертификат прошел проверку, для прочих соединений - пустую строку.
+func handshake(conn net.Conn, timeout time.Duration) (string, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return "", nil
+	}
+	if timeout <= 0 {
+		timeout = handshakeTimeout
+	}
+	tlsConn.SetDeadline(time.Now().Add(timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return "", err
+	}
+	tlsConn.SetDeadline(time.Time{})
+
+	state := tlsConn.ConnectionState()
+	if len(state.VerifiedChains) == 0 {
+		return "", nil
+	}
+	return peerName(state.VerifiedChains[0][0]), nil
+}
+
+// peerName - имя владельца сертификата: CN, либо первое DNS-имя.
+func peerName(cert *x509.Certificate) string {
+	if len(cert.Subject.CommonName) != 0 {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) != 0 {
+		return cert.DNSNames[0]
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,61 @@
+//go:build linux
+// +build linux
+
+package syslog
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// batchSupported - пакетное чтение (recvmmsg) поддерживается.
+	batchSupported = true
+)
+
+var (
+	// oobSize - размер буфера управляющих сообщений сокета (счетчик SO_RXQ_OVFL).
+	oobSize = unix.CmsgSpace(4)
+)
+
+// udpControl - настройка UDP-сокета перед открытием: включение счетчика
+// сообщений, отброшенных ядром (SO_RXQ_OVFL), и, при необходимости,
+// совместного использования порта несколькими сокетами (SO_REUSEPORT).
+func udpControl(reusePort bool) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if reusePort {
+				sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+				if sockErr != nil {
+					return
+				}
+			}
+			sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_RXQ_OVFL, 1)
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
+
+// parseDrops - извлечь из управляющих сообщений сокета количество
+// сообщений, отброшенных ядром с момента открытия сокета.
+func parseDrops(oob []byte) (uint32, bool) {
+	if len(oob) == 0 {
+		return 0, false
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return 0, false
+	}
+	for _, msg := range msgs {
+		if msg.Header.Level == unix.SOL_SOCKET && msg.Header.Type == unix.SO_RXQ_OVFL && len(msg.Data) >= 4 {
+			return binary.LittleEndian.Uint32(msg.Data), true
+		}
+	}
+	return 0, false
+}
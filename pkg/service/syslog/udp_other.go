@@ -0,0 +1,35 @@
+//go:build !linux
+// +build !linux
+
+package syslog
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// batchSupported - пакетное чтение поддерживается только в Linux.
+	batchSupported = false
+)
+
+var (
+	// oobSize - размер буфера управляющих сообщений сокета (не используется).
+	oobSize = 0
+)
+
+// udpControl - настройка UDP-сокета перед открытием.
+// SO_REUSEPORT поддерживается только в Linux.
+func udpControl(reusePort bool) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if reusePort {
+			return errors.New("multiple udp sockets are supported on linux only")
+		}
+		return nil
+	}
+}
+
+// parseDrops - счетчик сообщений, отброшенных ядром, не поддерживается.
+func parseDrops(oob []byte) (uint32, bool) {
+	return 0, false
+}
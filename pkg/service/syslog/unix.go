@@ -0,0 +1,103 @@
+package syslog
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// localSource - адрес отправителя сообщений, полученных через локальный сокет.
+	localSource = "127.0.0.1"
+)
+
+// NewUnixListener - создать новый экземпляр Listener для приема сообщений
+// через локальный сокет (например, /dev/log). network - "unixgram" (датаграммы)
+// или "unix" (поток, разделение сообщений по RFC 6587).
+// Оставшийся от предыдущего запуска файл сокета удаляется, права доступа
+// к созданному сокету устанавливаются в perm.
+func NewUnixListener(network, path string, perm os.FileMode, opts Options, parser parser.Parser) (Listener, error) {
+	if network != "unix" && network != "unixgram" {
+		return nil, fmt.Errorf("unix socket type \"%s\" are unknown", network)
+	}
+	h, err := newHandler(opts, parser)
+	if err != nil {
+		return nil, err
+	}
+	if err := removeSocket(path); err != nil {
+		return nil, err
+	}
+	addr := &net.UnixAddr{Name: path, Net: network}
+
+	var result Listener
+	if network == "unix" {
+		lsn, err := net.ListenUnix(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		result = newTCPListener(h, lsn, opts.IdleTimeout)
+	} else {
+		conn, err := net.ListenUnixgram(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		result = &unixListener{handler: h, conn: conn, path: path}
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		result.Close()
+		return nil, fmt.Errorf("set socket permissions err - %v", err)
+	}
+	log.Infof("listen syslog messages on %s socket %s", network, path)
+
+	return result, nil
+}
+
+// removeSocket - удалить существующий файл сокета.
+// Файлы других типов не удаляются - занять такой путь не удастся.
+func removeSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove stale socket err - %v", err)
+	}
+	return nil
+}
+
+// unixListener - реализация интерфейса Listener (локальный сокет датаграмм).
+type unixListener struct {
+	*handler
+	conn *net.UnixConn
+	path string
+}
+
+// Listen - запустить основной цикл - в цикле считывать сообщения
+// из сокета и передавать их в очередь обработки.
+func (l *unixListener) Listen(ch chan *pb.Event) {
+	l.start(ch)
+	buf := make([]byte, l.bufSize)
+	for {
+		n, _, err := l.conn.ReadFromUnix(buf)
+		if err != nil {
+			if isClosed(err) {
+				return
+			}
+			log.Debugf("listener recv err - %v", err)
+			continue
+		}
+		l.enqueue(&message{text: string(buf[:n]), source: localSource}, false)
+	}
+}
+
+// Close - завершить работу, закрыть и удалить сокет.
+func (l *unixListener) Close() {
+	l.conn.Close()
+	os.Remove(l.path)
+	l.stop()
+	log.Debugf("total: %s", l.Counters())
+}
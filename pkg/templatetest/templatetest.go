@@ -0,0 +1,181 @@
+// Package templatetest - проверка шаблонов обработчика на примерах сообщений
+// и сравнение результатов с эталонным (golden) файлом.
+package templatetest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+)
+
+const (
+	// ResultMatched - сообщение преобразовано в событие.
+	ResultMatched = "matched"
+	// ResultUnmatched - сообщение не совпало ни с одним шаблоном.
+	ResultUnmatched = "unmatched"
+	// ResultMalformed - ошибка обработки данных совпавшего шаблона.
+	ResultMalformed = "malformed"
+)
+
+// Result - результат обработки примера сообщения.
+type Result struct {
+	Line     int              // номер строки в файле примеров
+	Text     string           // пример сообщения (с заголовком syslog, если он указан)
+	Result   string           // результат (matched, unmatched, malformed)
+	Template string           // идентификатор совпавшего шаблона
+	Error    string           // текст ошибки обработчика
+	Event    *pb.Event        // событие (без данных заголовка syslog)
+	Header   *pb.SyslogHeader // данные заголовка syslog (nil - заголовок не распознан)
+}
+
+// golden - строка эталонного файла (JSON): результат обработки без номера строки и заголовка syslog.
+type golden struct {
+	Text     string          `json:"text"`
+	Result   string          `json:"result"`
+	Template string          `json:"template,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Event    json.RawMessage `json:"event,omitempty"`
+}
+
+// Run - обработать примеры сообщений (по одному в строке, пустые строки и строки,
+// начинающиеся с "#", пропускаются). Заголовок syslog отделяется от сообщения так же,
+// как при приеме сообщений сервисом, source - адрес отправителя сообщений,
+// loc - часовой пояс устройств для заголовков RFC 3164.
+func Run(p parser.Parser, samples io.Reader, source string, loc *time.Location) ([]Result, error) {
+	result := make([]Result, 0)
+	scanner := bufio.NewScanner(samples)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if len(strings.TrimSpace(text)) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		header, msg := syslog.DecodeHeader(text, loc)
+		r := Result{Line: line, Text: text, Header: header, Result: ResultMatched}
+		event, err := p.Parse(msg, source)
+		switch e := err.(type) {
+		case nil:
+			r.Event, r.Template = event, event.Template
+		case *parser.ErrUnknownFormat:
+			r.Result, r.Error = ResultUnmatched, e.Error()
+		case *parser.ErrDataParse:
+			r.Result, r.Error, r.Template = ResultMalformed, e.Error(), e.Template
+		default:
+			r.Result, r.Error = ResultMalformed, e.Error()
+		}
+		result = append(result, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read samples err - %v", err)
+	}
+	return result, nil
+}
+
+// marshal - сформировать строку эталонного файла для результата.
+func marshal(r Result) (string, error) {
+	g := golden{Text: r.Text, Result: r.Result, Template: r.Template, Error: r.Error}
+	if r.Event != nil {
+		m := jsonpb.Marshaler{OrigName: true}
+		event, err := m.MarshalToString(r.Event)
+		if err != nil {
+			return "", fmt.Errorf("encode event err - %v", err)
+		}
+		g.Event = json.RawMessage(event)
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(g); err != nil {
+		return "", fmt.Errorf("encode result err - %v", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// WriteGolden - записать результаты в эталонный файл (JSON, по результату в строке).
+func WriteGolden(w io.Writer, results []Result) error {
+	for _, r := range results {
+		line, err := marshal(r)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("write golden err - %v", err)
+		}
+	}
+	return nil
+}
+
+// CompareGolden - сравнить результаты с эталонным файлом, вернуть описания несовпадений
+// (пусто - результаты совпадают). Результаты сопоставляются с записями файла по тексту
+// сообщения (повторяющиеся сообщения - в порядке следования), поэтому добавление или удаление
+// примера не влияет на сравнение остальных.
+func CompareGolden(in io.Reader, results []Result) ([]string, error) {
+	type entry struct {
+		line string
+		used bool
+	}
+	entries := make([]*entry, 0, len(results))
+	byText := make(map[string][]*entry)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		var g golden
+		if err := json.Unmarshal([]byte(line), &g); err != nil {
+			return nil, fmt.Errorf("parse golden line %d err - %v", n, err)
+		}
+		e := &entry{line: line}
+		entries = append(entries, e)
+		byText[g.Text] = append(byText[g.Text], e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read golden err - %v", err)
+	}
+
+	diff := make([]string, 0)
+	for _, r := range results {
+		actual, err := marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		queue := byText[r.Text]
+		if len(queue) == 0 {
+			diff = append(diff, fmt.Sprintf("line %d: unexpected result\n  actual:   %s", r.Line, actual))
+			continue
+		}
+		e := queue[0]
+		byText[r.Text], e.used = queue[1:], true
+		if !sameJSON(e.line, actual) {
+			diff = append(diff, fmt.Sprintf("line %d: result mismatch\n  expected: %s\n  actual:   %s", r.Line, e.line, actual))
+		}
+	}
+	for _, e := range entries {
+		if !e.used {
+			diff = append(diff, fmt.Sprintf("missing result\n  expected: %s", e.line))
+		}
+	}
+	return diff, nil
+}
+
+// sameJSON - сравнить строки JSON без учета форматирования и порядка ключей.
+func sameJSON(a, b string) bool {
+	var x, y interface{}
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+		return a == b
+	}
+	xs, _ := json.Marshal(x)
+	ys, _ := json.Marshal(y)
+	return bytes.Equal(xs, ys)
+}
@@ -1,10 +1,20 @@
 package test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
 	"testing"
+	"time"
 
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
 	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
 	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+	"google.golang.org/grpc"
 )
 
 func TestSyslogCatcher(t *testing.T) {
@@ -18,3 +28,88 @@ func TestSyslogCatcher(t *testing.T) {
 	}
 	s.Close()
 }
+
+func TestSyslogCatcherStats(t *testing.T) {
+	cfg, err := parseConfig(t, `
+log: {level: info, file: catcher.log}
+grpc: {listen: "127.0.0.1:55528"}
+metrics: {listen: "127.0.0.1:55529"}
+syslog:
+  listen: "127.0.0.1:55530"
+  buf_size: 1500
+  templates:
+    - "link_down [id=down] ~ port $device_port$ down"
+    - "link_up [id=up] ~ port $device_port$ up"
+    - "loopdetect [id=loop] ~ port $device_port$ loop"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := catcher.NewService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	defer s.Close()
+
+	conn, err := net.Dial("udp", "127.0.0.1:55530")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, text := range []string{"port 1 down", "port 2 down", "port X down", "port 3 up"} {
+		fmt.Fprint(conn, text)
+	}
+
+	grpcConn, err := grpc.Dial("127.0.0.1:55528", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcConn.Close()
+	client := pb.NewSyslogCatcherClient(grpcConn)
+	stats := make(map[string]*pb.TemplateStats)
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+		rs, err := client.Templates(context.Background(), &pb.TemplateStatsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range rs.GetTemplates() {
+			stats[v.GetID()] = v
+		}
+		if stats["up"].GetHits() == 1 {
+			break
+		}
+	}
+	if len(stats) != 3 || stats["down"].GetHits() != 2 || stats["down"].GetErrors() != 1 || stats["down"].GetLastMatch() == 0 ||
+		stats["up"].GetHits() != 1 || stats["up"].GetMisses() != 0 || stats["loop"].GetHits() != 0 ||
+		stats["down"].GetParser() != "common" || stats["down"].GetTemplate() != "link_down ~ port $device_port$ down" {
+		t.Fatal("unexpected result - template stats not match with criterias", stats)
+	}
+	rs, err := client.Templates(context.Background(), &pb.TemplateStatsRequest{Unused: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.GetTemplates()) != 1 || rs.GetTemplates()[0].GetID() != "loop" {
+		t.Fatal("unexpected result - unused templates not match with criterias", rs.GetTemplates())
+	}
+
+	resp, err := http.Get("http://127.0.0.1:55529/debug/vars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var vars struct {
+		Memstats   map[string]interface{}            `json:"memstats"`
+		Listeners  map[string]syslog.Counters        `json:"syslog_listeners"`
+		Suppressed map[string]map[string]uint64      `json:"syslog_suppressed"`
+		Templates  map[string][]parser.TemplateStats `json:"syslog_templates"`
+		Letters    map[string]uint64                 `json:"syslog_dead_letters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		t.Fatal(err)
+	}
+	if len(vars.Memstats) == 0 || vars.Listeners["listener #1"].Recv != 4 || vars.Suppressed == nil || vars.Letters == nil ||
+		len(vars.Templates["common"]) != 3 || vars.Templates["common"][0].Hits != 2 {
+		t.Fatal("unexpected result - metrics not match with criterias", vars)
+	}
+}
@@ -0,0 +1,178 @@
+package test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+)
+
+// parseConfig - загрузить конфигурацию из текста.
+func parseConfig(t *testing.T, text string) (*config.Config, error) {
+	f, err := ioutil.TempFile("", "catcher-config-*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	return config.ParseFile(f.Name())
+}
+
+func TestConfigListeners(t *testing.T) {
+	// Единственный обработчик, заданный параметром listen.
+	cfg, err := parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  listen: ":51514"
+  buf_size: 1500
+  templates: ["link_down ~ $device_addr$ port $device_port$ down"]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Syslog.Listeners) != 1 || cfg.Syslog.Listeners[0].Protocol != "udp" ||
+		cfg.Syslog.Listeners[0].Listen != ":51514" || cfg.Syslog.Listeners[0].BufSize != 1500 {
+		t.Fatal("unexpected result - legacy listener not match with criterias", cfg.Syslog.Listeners)
+	}
+
+	// Список обработчиков с общими и собственными параметрами.
+	cfg, err = parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  buf_size: 1500
+  idle_timeout: 1m
+  listeners:
+    - {protocol: udp, listen: ":51514", templates: ["link_down ~ $device_addr$ port $device_port$ down"]}
+    - {protocol: tcp, listen: "127.0.0.1:51601", buf_size: 8192, templates: ["link_up ~ $device_addr$ port $device_port$ up"]}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Syslog.Listeners) != 2 || cfg.Syslog.Listeners[1].BufSize != 8192 ||
+		cfg.Syslog.Listeners[1].IdleTimeout != time.Minute || cfg.Syslog.Listeners[0].BufSize != 1500 {
+		t.Fatal("unexpected result - listeners not match with criterias", cfg.Syslog.Listeners)
+	}
+
+	// Локальный сокет с правами доступа по умолчанию.
+	cfg, err = parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  buf_size: 1500
+  templates: ["link_down ~ port $device_port$ down"]
+  listeners:
+    - {protocol: unixgram, listen: "/run/syslog-catcher/log.sock"}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Syslog.Listeners[0].Mode != 0666 {
+		t.Fatal("unexpected result - socket mode not match with criterias", cfg.Syslog.Listeners[0].Mode)
+	}
+
+	// Типы событий с некорректным уровнем важности.
+	_, err = parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  listen: ":51514"
+  buf_size: 1500
+  templates: ["psu_failure ~ power supply $ignore$ failed"]
+  event_types:
+    - {name: psu_failure, severity: fatal}
+`)
+	if err == nil {
+		t.Fatal("unexpected result - invalid event type severity is accepted")
+	}
+
+	// Обработчик с неизвестным набором правил имен интерфейсов.
+	_, err = parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  buf_size: 1500
+  templates: ["link_down ~ port $device_port$ down"]
+  interfaces:
+    cisco: {gi: GigabitEthernet}
+  listeners:
+    - {protocol: udp, listen: ":51514", vendor: juniper}
+`)
+	if err == nil {
+		t.Fatal("unexpected result - unknown vendor is accepted")
+	}
+
+	// Обработчик без шаблонов при отсутствии общего набора шаблонов.
+	_, err = parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  buf_size: 1500
+  listeners:
+    - {protocol: udp, listen: ":51514"}
+`)
+	if err == nil {
+		t.Fatal("unexpected result - config without templates is accepted")
+	}
+}
+
+func TestConfigRateLimit(t *testing.T) {
+	// Обработчик наследует общее ограничение интенсивности, если не задано собственное
+	// и ограничение не отключено.
+	cfg, err := parseConfig(t, `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  buf_size: 1500
+  templates: ["link_down ~ port $device_port$ down"]
+  rate_limit: {source_rate: 100}
+  listeners:
+    - {protocol: udp, listen: ":51514"}
+    - {protocol: udp, listen: ":51515", rate_limit: {source_rate: 10}}
+    - {protocol: udp, listen: ":51516", rate_limit: {disabled: true}}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := cfg.Syslog.Listeners
+	if l[0].RateLimit.SourceRate != 100 || l[1].RateLimit.SourceRate != 10 ||
+		!l[2].RateLimit.Disabled || l[2].RateLimit.SourceRate != 0 {
+		t.Fatal("unexpected result - rate limits not match with criterias", l)
+	}
+}
+
+func TestConfigInventory(t *testing.T) {
+	// Относительный путь к справочнику отсчитывается от каталога файла конфигурации.
+	text := `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  listen: ":51514"
+  buf_size: 1500
+  templates: ["link_down ~ port $device_port$ down"]
+inventory: {file: %s}
+`
+	cfg, err := parseConfig(t, fmt.Sprintf(text, "inventory.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Inventory.File != filepath.Join(os.TempDir(), "inventory.csv") {
+		t.Fatal("unexpected result - inventory file not match with criterias", cfg.Inventory.File)
+	}
+	abs := filepath.Join(os.TempDir(), "devices", "inventory.csv")
+	if cfg, err = parseConfig(t, fmt.Sprintf(text, abs)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Inventory.File != abs {
+		t.Fatal("unexpected result - inventory file not match with criterias", cfg.Inventory.File)
+	}
+}
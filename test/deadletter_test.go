@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/deadletter"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestListenerFailed(t *testing.T) {
+	p, err := parser.NewParser(patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := deadletter.New(deadletter.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	letters := store.Subscribe(10)
+
+	addr := "127.0.0.1:55527"
+	opts := syslog.Options{BufSize: 1500, Workers: 1, Failed: func(text, source string, err error) {
+		store.Write(deadletter.NewRecord(text, source, err))
+	}}
+	lsn, err := syslog.NewListener(addr, opts, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event, 10)
+	go lsn.Listen(ch)
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "192.168.1.10 - - - port X change link state to down")
+	fmt.Fprint(conn, "<30>Oct 11 22:14:15 sw1 ifmgr: Interface 5 changed state to down")
+
+	expected := []struct {
+		Reason   string
+		Template bool
+		Text     string
+	}{
+		{deadletter.ReasonMalformed, true, "192.168.1.10 - - - port X change link state to down"},
+		{deadletter.ReasonUnmatched, false, "Interface 5 changed state to down"},
+	}
+	for _, v := range expected {
+		select {
+		case rec := <-letters:
+			if rec.Reason != v.Reason || (len(rec.Template) != 0) != v.Template || rec.Text != v.Text ||
+				rec.Source != "127.0.0.1" || len(rec.Error) == 0 || rec.Time.IsZero() {
+				t.Fatal("unexpected result - dead letter not match with criterias", rec)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - dead letter is not received", lsn.Counters())
+		}
+	}
+}
+
+func TestDeadLetterStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "deadletter.log")
+	store, err := deadletter.New(deadletter.Options{File: path, MaxSize: 1024, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		store.Write(deadletter.NewRecord(fmt.Sprintf("message %d", i), "10.0.0.1", &parser.ErrUnknownFormat{Message: "unknown format"}))
+	}
+	store.Close()
+
+	names, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 {
+		t.Fatal("unexpected result - rotated files not match with criterias", names)
+	}
+	var last *deadletter.Record
+	for _, name := range names {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 1024 {
+			t.Fatal("unexpected result - file size exceeds limit", name, info.Size())
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		last = &deadletter.Record{}
+		if err := json.Unmarshal(scanner.Bytes(), last); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if last == nil || last.Text != "message 99" || last.Reason != deadletter.ReasonUnmatched || last.Source != "10.0.0.1" {
+		t.Fatal("unexpected result - last record not match with criterias", last)
+	}
+
+	if _, err := deadletter.New(deadletter.Options{File: path, MaxSize: -1}); err == nil {
+		t.Fatal("unexpected result - invalid dead letter params are accepted")
+	}
+}
+
+func TestDeadLetterStoreOpenFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	store, err := deadletter.New(deadletter.Options{File: filepath.Join(dir, "deadletter.log"), MaxSize: 256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Каталог удален - файл не открывается после ротации, ошибка выводится однократно.
+	os.RemoveAll(dir)
+	for i := 0; i < 100; i++ {
+		store.Write(deadletter.NewRecord(fmt.Sprintf("message %d", i), "10.0.0.1", &parser.ErrUnknownFormat{Message: "unknown format"}))
+	}
+	store.Close()
+
+	errors := 0
+	for _, e := range hook.AllEntries() {
+		if strings.Contains(e.Message, "open dead letter file") {
+			errors++
+		}
+	}
+	if errors != 1 || store.Dropped() < 90 {
+		t.Fatal("unexpected result - open errors not match with criterias", errors, store.Dropped())
+	}
+}
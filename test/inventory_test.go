@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
+	"github.com/neurovillain/syslog-catcher/pkg/service/inventory"
+	"google.golang.org/grpc"
+)
+
+// writeInventory - записать файл справочника устройств во временный каталог.
+func writeInventory(t *testing.T, dir, name, text string) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestInventoryLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-inventory-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"devices.csv": `# comment
+Addr, Hostname, Site, Vendor, Model, Role, Serial
+10.0.0.1, sw-1, msk, Cisco, Catalyst 2960, access, FOC123
+`,
+		"devices.yml": `
+- {addr: 10.0.0.1, hostname: sw-1, site: msk, vendor: Cisco, model: Catalyst 2960, role: access}
+`,
+		"devices.json": `[{"addr": "10.0.0.1", "hostname": "sw-1", "site": "msk", "vendor": "Cisco", "model": "Catalyst 2960", "role": "access"}]`,
+	}
+	for name, text := range files {
+		devices, err := inventory.Load(writeInventory(t, dir, name, text))
+		if err != nil {
+			t.Fatalf("%s - %v", name, err)
+		}
+		d := devices["10.0.0.1"]
+		if len(devices) != 1 || d.GetHostname() != "sw-1" || d.GetSite() != "msk" || d.GetVendor() != "Cisco" ||
+			d.GetModel() != "Catalyst 2960" || d.GetRole() != "access" {
+			t.Fatalf("%s - unexpected result - devices not match with criterias - %v", name, devices)
+		}
+	}
+
+	invalid := map[string]string{
+		"addr.csv":   "addr,hostname\nsw-1,sw-1\n",
+		"column.csv": "hostname,site\nsw-1,msk\n",
+		"field.yml":  "- {addr: 10.0.0.1, location: msk}\n",
+		"format.txt": "10.0.0.1 sw-1\n",
+	}
+	for name, text := range invalid {
+		if _, err := inventory.Load(writeInventory(t, dir, name, text)); err == nil {
+			t.Errorf("%s - expected error", name)
+		}
+	}
+}
+
+func TestInventoryReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-inventory-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := writeInventory(t, dir, "devices.csv", "addr,hostname,site\n10.0.0.1,sw-1,msk\n")
+	inv, err := inventory.New(inventory.Options{File: file, Reload: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inv.Close()
+
+	event := &pb.Event{Host: "10.0.0.1"}
+	inv.Enrich(event)
+	if event.GetDevice().GetHostname() != "sw-1" || inv.Lookup("10.0.0.2") != nil {
+		t.Fatal("unexpected result - device not match with criterias", event)
+	}
+
+	// Ошибка загрузки измененного файла - сохраняются прежние данные.
+	writeInventory(t, dir, "devices.csv", "addr,hostname,site\nsw-2,sw-2,spb\n")
+	time.Sleep(50 * time.Millisecond)
+	if inv.Lookup("10.0.0.1").GetSite() != "msk" {
+		t.Fatal("unexpected result - inventory data lost after invalid reload")
+	}
+
+	writeInventory(t, dir, "devices.csv", "addr,hostname,site\n10.0.0.1,sw-1,spb\n10.0.0.2,sw-2,spb\n")
+	for start := time.Now(); time.Since(start) < time.Second && inv.Lookup("10.0.0.2") == nil; {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if inv.Lookup("10.0.0.1").GetSite() != "spb" || inv.Lookup("10.0.0.2").GetHostname() != "sw-2" {
+		t.Fatal("unexpected result - inventory not reloaded")
+	}
+}
+
+func TestSyslogCatcherInventory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-inventory-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := writeInventory(t, dir, "devices.yml", `
+- {addr: 10.0.0.1, hostname: sw-1, site: msk, role: access}
+- {addr: 10.0.0.2, hostname: core-1, site: spb, role: core}
+`)
+
+	cfg, err := parseConfig(t, fmt.Sprintf(`
+log: {level: info, file: catcher.log}
+grpc: {listen: "127.0.0.1:55531"}
+inventory: {file: %q}
+syslog:
+  listen: "127.0.0.1:55532"
+  buf_size: 1500
+  templates: ["link_down ~ $device_addr$ port $device_port$ down"]
+`, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := catcher.NewService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	defer s.Close()
+
+	grpcConn, err := grpc.Dial("127.0.0.1:55531", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcConn.Close()
+	client := pb.NewSyslogCatcherClient(grpcConn)
+	conn, err := net.Dial("udp", "127.0.0.1:55532")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		Request *pb.EventRequest
+		Host    string
+	}{
+		{&pb.EventRequest{ClientName: "sites", Events: []pb.EventType{pb.EventType_PortDown}, Sites: []string{"msk"}}, "10.0.0.1"},
+		{&pb.EventRequest{ClientName: "roles", Events: []pb.EventType{pb.EventType_PortDown}, Roles: []string{"core"}}, "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		stream, err := client.Events(ctx, tt.Request)
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+		// Подписчик регистрируется асинхронно - сообщения отправляются до получения первого события.
+		recv := make(chan *pb.Event, 16)
+		go func() {
+			for {
+				event, err := stream.Recv()
+				if err != nil {
+					close(recv)
+					return
+				}
+				recv <- event
+			}
+		}()
+		var event *pb.Event
+		for event == nil {
+			fmt.Fprint(conn, "10.0.0.3 port 1 down")
+			fmt.Fprint(conn, "10.0.0.1 port 1 down")
+			fmt.Fprint(conn, "10.0.0.2 port 2 down")
+			select {
+			case event = <-recv:
+			case <-time.After(20 * time.Millisecond):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		cancel()
+		if event.GetHost() != tt.Host || event.GetDevice().GetHostname() == "" {
+			t.Fatalf("%s - unexpected result - event not match with criterias - %v", tt.Request.ClientName, event)
+		}
+		for event := range recv {
+			if event.GetHost() != tt.Host {
+				t.Fatalf("%s - unexpected event - %v", tt.Request.ClientName, event)
+			}
+		}
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/mock"
+	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
+	"github.com/neurovillain/syslog-catcher/pkg/service/deadletter"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"google.golang.org/grpc"
+)
+
+func TestLearner(t *testing.T) {
+	l, err := parser.NewLearner(parser.LearnOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Сообщения оборудования, для которого нет шаблонов.
+	n := 8
+	gen := mock.NewGenerator(1)
+	for i := 0; i < 400; i++ {
+		l.Add(gen.VendorMessage(n))
+	}
+	l.Add("10.0.0.1 - - - port 3 change link state to up with 2.5G FULL-duplex")
+	l.Add("10.0.0.2 - - - port Ethernet1/0/7 change link state to up with 100MB HALF-duplex")
+
+	result := l.Suggest(2, 0)
+	if len(result) != n+1 {
+		t.Fatal("unexpected result - count of suggestions not match with criterias", len(result), result)
+	}
+	for k := 1; k < len(result); k++ {
+		if result[k].Count > result[k-1].Count {
+			t.Fatal("unexpected result - suggestions are not sorted by count", result)
+		}
+	}
+	templates := make([]string, 0, len(result))
+	for _, v := range result {
+		templates = append(templates, v.Template)
+	}
+	// Предлагаемые шаблоны принимаются обработчиком и совпадают с примерами сообщений группы.
+	p, err := parser.NewParser(templates, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range result {
+		if len(v.Examples) == 0 || !strings.Contains(v.Pattern, "<*>") {
+			t.Fatal("unexpected result - suggestion not match with criterias", v)
+		}
+		for _, text := range v.Examples {
+			event, err := p.Parse(text, "")
+			if err != nil {
+				t.Fatal("unexpected result - suggested template not match example", v.Template, text, err)
+			}
+			if len(event.Host) == 0 || event.Port == 0 && !strings.Contains(text, "Interface 0 ") {
+				t.Fatal("unexpected result - event fields not match with criterias", v.Template, event)
+			}
+		}
+	}
+	for _, v := range result {
+		if strings.Contains(v.Pattern, "change link state to up with") &&
+			v.Template != "link_up ~ $device_addr$ - - - port $device_port$ change link state to up with $port_speed$ $port_duplex$" {
+			t.Fatal("unexpected result - suggested template not match with criterias", v.Template)
+		}
+	}
+
+	if s := l.Suggest(1000, 0); len(s) != 0 {
+		t.Fatal("unexpected result - min count is ignored", s)
+	}
+	if s := l.Suggest(1, 3); len(s) != 3 {
+		t.Fatal("unexpected result - limit is ignored", s)
+	}
+}
+
+func TestLearnerMaxClusters(t *testing.T) {
+	l, err := parser.NewLearner(parser.LearnOptions{MaxClusters: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		l.Add(fmt.Sprintf("event%c message %d", 'a'+i, i))
+	}
+	result := l.Suggest(0, 0)
+	if len(result) != 5 || result[0].Pattern != "eventp message <*>" {
+		t.Fatal("unexpected result - clusters are not evicted", result)
+	}
+
+	for _, opts := range []parser.LearnOptions{{MaxClusters: -1}, {Similarity: 1.5}, {Depth: -1}} {
+		if _, err := parser.NewLearner(opts); err == nil {
+			t.Fatal("unexpected result - invalid learn params are accepted", opts)
+		}
+	}
+}
+
+func TestLearnerTemplateMarkup(t *testing.T) {
+	l, err := parser.NewLearner(parser.LearnOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Слова сообщения, совпадающие с разметкой шаблона, не должны менять смысл шаблона.
+	examples := []string{
+		"10.0.0.1 port 3 cost $cost$ changed ~re~ link down",
+		"10.0.0.2 port 7 cost $cost$ changed ~re~ link down",
+	}
+	for _, text := range examples {
+		l.Add(text)
+	}
+	result := l.Suggest(2, 0)
+	if len(result) != 1 || result[0].Template != "link_down ~ $device_addr$ port $device_port$ cost $ignore$ changed $ignore$ link down" {
+		t.Fatal("unexpected result - suggested template not match with criterias", result)
+	}
+	p, err := parser.NewParser([]string{result[0].Template}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range examples {
+		event, err := p.Parse(text, "")
+		if err != nil || event.Port == 0 || len(event.Attributes) != 0 {
+			t.Fatal("unexpected result - suggested template not match example", text, event, err)
+		}
+	}
+}
+
+func TestSyslogCatcherUnmatched(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-unmatched")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "deadletter.log")
+
+	cfg, err := parseConfig(t, fmt.Sprintf(`
+log: {level: info, file: catcher.log}
+grpc: {listen: "127.0.0.1:55534"}
+syslog:
+  listen: "127.0.0.1:55535"
+  buf_size: 1500
+  workers: 1
+  templates: ["link_down ~ $device_addr$ - - - port $device_port$ change link state to down"]
+  dead_letter: {file: %q}
+`, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := catcher.NewService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	closed := false
+	defer func() {
+		if !closed {
+			s.Close()
+		}
+	}()
+
+	conn, err := net.Dial("udp", "127.0.0.1:55535")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// Ошибка данных совпавшего шаблона не учитывается при группировке,
+	// но сохраняется в хранилище вместе с несовпавшим сообщением.
+	fmt.Fprint(conn, "192.168.1.10 - - - port X change link state to down")
+	fmt.Fprint(conn, "<30>Oct 11 22:14:15 sw1 ifmgr: Interface 5 changed state to down")
+
+	grpcConn, err := grpc.Dial("127.0.0.1:55534", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcConn.Close()
+	client := pb.NewSyslogCatcherClient(grpcConn)
+	var suggestions []*pb.Suggestion
+	for start := time.Now(); time.Since(start) < time.Second && len(suggestions) == 0; time.Sleep(10 * time.Millisecond) {
+		rs, err := client.Suggestions(context.Background(), &pb.SuggestRequest{MinCount: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		suggestions = rs.GetSuggestions()
+	}
+	if len(suggestions) != 1 || fmt.Sprint(suggestions[0].GetExamples()) != "[Interface 5 changed state to down]" {
+		t.Fatal("unexpected result - suggestions not match with criterias", suggestions)
+	}
+
+	s.Close()
+	closed = true
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reasons := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rec := &deadletter.Record{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			t.Fatal(err)
+		}
+		reasons = append(reasons, rec.Reason)
+	}
+	if fmt.Sprint(reasons) != fmt.Sprint([]string{deadletter.ReasonMalformed, deadletter.ReasonUnmatched}) {
+		t.Fatal("unexpected result - dead letters not match with criterias", reasons)
+	}
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
+	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+)
+
+// writePacks - создать каталог конфигурации с файлом service.yml и наборами шаблонов (имя файла - содержимое).
+func writePacks(t *testing.T, cfg string, packs map[string]string) string {
+	dir, err := ioutil.TempDir("", "catcher-packs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "packs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range packs {
+		if err := ioutil.WriteFile(filepath.Join(dir, "packs", name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "service.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+const packsConfig = `
+log: {level: debug, file: catcher.log}
+grpc: {listen: ":61614"}
+syslog:
+  listen: ":51514"
+  buf_size: 1500
+  template_packs: packs
+  templates: ["link_down ~ $device_addr$ port $device_port$ down"]
+`
+
+func TestTemplatePacks(t *testing.T) {
+	dir := writePacks(t, packsConfig, map[string]string{
+		"a-cisco.yml": `
+vendor: Cisco
+version: "1.2"
+nets: ["10.10.0.0/16"]
+templates: ['link_down [id=cisco-down] ~re~ ^Interface (?P<device_port>[^,]+), changed state to down$']
+`,
+		"b-any.yaml": `
+name: generic
+templates: ['link_up [id=any-up] ~re~ ^Interface (?P<device_port>[^,]+), changed state to up$']
+`,
+		"README.txt": "not a pack",
+	})
+	defer os.RemoveAll(dir)
+
+	cfg, err := config.ParseFile(filepath.Join(dir, "service.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Syslog.Packs) != 2 || cfg.Syslog.Packs[0].Name != "a-cisco" || cfg.Syslog.Packs[0].Vendor != "Cisco" ||
+		cfg.Syslog.Packs[0].Version != "1.2" || cfg.Syslog.Packs[1].Name != "generic" {
+		t.Fatalf("unexpected result - packs not match with criterias - %+v", cfg.Syslog.Packs)
+	}
+
+	p, err := catcher.NewListenerParser(cfg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text     string
+		Source   string
+		Template string
+		OK       bool
+	}{
+		// Набор применяется только к сообщениям устройств из своих сетей.
+		{"Interface Gi0/1, changed state to down", "10.10.1.1", "cisco-down", true},
+		{"Interface Gi0/1, changed state to down", "10.20.1.1", "", false},
+		// Набор без сетей применяется ко всем устройствам.
+		{"Interface Gi0/1, changed state to up", "192.168.1.1", "any-up", true},
+		// Шаблоны конфигурации применяются после наборов.
+		{"192.168.1.99 port 5 down", "10.10.1.1", "", true},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, tt.Source)
+		if !tt.OK {
+			if _, ok := err.(*parser.ErrUnknownFormat); !ok {
+				t.Fatalf("%q from %s - expected unknown format, got %v, %v", tt.Text, tt.Source, event, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q from %s - %v", tt.Text, tt.Source, err)
+		}
+		if len(tt.Template) != 0 && event.GetTemplate() != tt.Template {
+			t.Fatalf("%q from %s - expected template %s, got %s", tt.Text, tt.Source, tt.Template, event.GetTemplate())
+		}
+	}
+
+	stats := p.Stats()
+	if len(stats) != 3 || stats[0].Pack != "a-cisco" || stats[0].Hits != 1 || stats[1].Pack != "generic" ||
+		stats[2].Pack != "" || stats[2].Hits != 1 {
+		t.Fatalf("unexpected result - stats not match with criterias - %+v", stats)
+	}
+}
+
+func TestTemplatePacksInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no templates": {"a.yml": "vendor: Cisco\n"},
+		"invalid net":  {"a.yml": "nets: [\"10.10.0.0/33\"]\ntemplates: ['link_up ~ port $device_port$ up']\n"},
+		"duplicate name": {
+			"a.yml": "name: pack\ntemplates: ['link_up ~ port $device_port$ up']\n",
+			"b.yml": "name: pack\ntemplates: ['link_down ~ port $device_port$ down']\n",
+		},
+		"unknown interfaces": {"a.yml": "interfaces: cisco\ntemplates: ['link_up ~ port $device_port$ up']\n"},
+	}
+	for name, packs := range tests {
+		dir := writePacks(t, packsConfig, packs)
+		if _, err := config.ParseFile(filepath.Join(dir, "service.yml")); err == nil {
+			t.Errorf("%s - expected error", name)
+		}
+		os.RemoveAll(dir)
+	}
+}
@@ -2,10 +2,14 @@ package test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/mock"
 	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 )
 
 var (
@@ -60,8 +64,14 @@ var (
 			OK:   false,
 		},
 		{
-			Text: "192.168.1.104 - - - port 6 change link state to up with 999mb half-duplex",
-			OK:   false,
+			// Нестандартная скорость не является ошибкой обработки сообщения.
+			Text:   "192.168.1.104 - - - port 6 change link state to up with 999mb half-duplex",
+			Type:   pb.EventType_PortUp,
+			Host:   "192.168.1.104",
+			Port:   6,
+			Speed:  pb.PortSpeed_UnknownSpeed,
+			Duplex: pb.PortDuplex_Half,
+			OK:     true,
 		},
 		{
 			Text: "192.168.1.105 - - - port 7 change link state to down",
@@ -95,15 +105,20 @@ var (
 	}
 )
 
+// sameEvent - сравнить основные поля событий (тип, адрес, порт и параметры соединения).
+func sameEvent(a, b *pb.Event) bool {
+	return a.Type == b.Type && a.Host == b.Host && a.Port == b.Port && a.Speed == b.Speed && a.Duplex == b.Duplex
+}
+
 func TestParser(t *testing.T) {
 
-	p, err := parser.NewParser(patterns)
+	p, err := parser.NewParser(patterns, parser.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for _, msg := range messages {
-		event, err := p.Parse(msg.Text)
+		event, err := p.Parse(msg.Text, "")
 		if err != nil {
 			if msg.OK {
 				t.Fatal("unexpected result - failed to parse normal message", err)
@@ -116,3 +131,793 @@ func TestParser(t *testing.T) {
 		fmt.Println(event)
 	}
 }
+
+func TestParserRegex(t *testing.T) {
+	p, err := parser.NewParser(append([]string{
+		`link_up ~re~ ^Interface (?P<device_port>\S+), changed state to up \(speed:(?P<port_speed>\d+), duplex:(?P<port_duplex>\w+)\)$`,
+		`link_down ~re~ ^(?:Host (?P<device_addr>[0-9.]+): )?Interface (?P<device_port>\S+), changed state to (?:administratively )?down$`,
+	}, patterns...), parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text   string
+		Event  *pb.Event
+		Source string
+		OK     bool
+	}{
+		{
+			Text:   "Interface Ethernet1/0/3, changed state to up (speed:100, duplex:full)",
+			Source: "192.168.1.110",
+			Event:  &pb.Event{Type: pb.EventType_PortUp, Host: "192.168.1.110", Port: 3, Speed: pb.PortSpeed_Speed100Mb, Duplex: pb.PortDuplex_Full},
+			OK:     true,
+		},
+		{
+			Text:   "Interface Ethernet1/0/4, changed state to administratively down",
+			Source: "192.168.1.111",
+			Event:  &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.111", Port: 4},
+			OK:     true,
+		},
+		{
+			Text:   "Host 192.168.1.112: Interface Ethernet1/0/5, changed state to down",
+			Source: "192.168.1.1",
+			Event:  &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.112", Port: 5},
+			OK:     true,
+		},
+		{
+			// Текстовые шаблоны продолжают работать совместно с регулярными выражениями.
+			Text:  "192.168.1.113 - - - port 6 change link state to down",
+			Event: &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.113", Port: 6},
+			OK:    true,
+		},
+		{
+			Text: "Interface Ethernet1/0/7, changed state to up (speed:100, duplex:auto)",
+			OK:   false,
+		},
+		{
+			Text: "Interface Ethernet1/0/8, changed state to testing",
+			OK:   false,
+		},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, tt.Source)
+		if err != nil {
+			if tt.OK {
+				t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+			}
+			continue
+		}
+		if !tt.OK || !sameEvent(event, tt.Event) {
+			t.Fatal("unexpected result - parse result not match with criterias", tt.Text, event)
+		}
+	}
+
+	// Некорректное выражение.
+	if _, err := parser.NewParser([]string{`link_up ~re~ port (\d+`}, parser.Options{}); err == nil {
+		t.Fatal("unexpected result - invalid regex pattern is accepted")
+	}
+}
+
+func TestParserPatternLibrary(t *testing.T) {
+	p, err := parser.NewParser([]string{
+		`link_up ~re~ ^%{IP:device_addr}: %{IFNAME:device_port} is up, %{SPEED:port_speed} %{DUPLEX:port_duplex}$`,
+		`link_down ~re~ ^%{PORT:device_port} link down$`,
+	}, parser.Options{
+		Patterns: map[string]string{
+			"PORT": `Port %{INT}(?::%{INT})?`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text  string
+		Event *pb.Event
+	}{
+		{
+			Text:  "192.168.1.120: GigabitEthernet1/0/12 is up, 1000Mb/s full-duplex",
+			Event: &pb.Event{Type: pb.EventType_PortUp, Host: "192.168.1.120", Port: 12, Speed: pb.PortSpeed_Speed1Gb, Duplex: pb.PortDuplex_Full},
+		},
+		{
+			Text:  "fe80::1: eth0/3 is up, 100M half",
+			Event: &pb.Event{Type: pb.EventType_PortUp, Host: "fe80::1", Port: 3, Speed: pb.PortSpeed_Speed100Mb, Duplex: pb.PortDuplex_Half},
+		},
+		{
+			Text:  "Port 1:14 link down",
+			Event: &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.121", Port: 14},
+		},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.121")
+		if err != nil {
+			t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+		}
+		if !sameEvent(event, tt.Event) {
+			t.Fatal("unexpected result - parse result not match with criterias", tt.Text, event)
+		}
+	}
+
+	// Неизвестные, рекурсивные и некорректные выражения.
+	invalid := []parser.Options{
+		{Patterns: map[string]string{"LOOP": `%{LOOP}`}},
+		{Patterns: map[string]string{"BAD": `(\d+`}},
+		{Patterns: map[string]string{"BAD NAME": `\d+`}},
+		{Patterns: map[string]string{"REF": `%{MISSING}`}},
+	}
+	for _, opts := range invalid {
+		if _, err := parser.NewParser([]string{`link_down ~re~ %{INT:device_port}`}, opts); err == nil {
+			t.Fatal("unexpected result - invalid pattern library is accepted", opts)
+		}
+	}
+	if _, err := parser.NewParser([]string{`link_down ~re~ %{MISSING:device_port}`}, parser.Options{}); err == nil {
+		t.Fatal("unexpected result - unknown pattern is accepted")
+	}
+}
+
+func TestParserPatternIP(t *testing.T) {
+	p, err := parser.NewParser([]string{
+		`link_down ~re~ %{IP:device_addr} .*port (?P<device_port>\d+) down`,
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Время и MAC-адрес перед адресом устройства не принимаются за адрес IPv6.
+	tests := []struct {
+		Text string
+		Host string
+	}{
+		{Text: "10:15:20 192.168.1.5 port 3 down", Host: "192.168.1.5"},
+		{Text: "00:11:22:33:44:55 192.168.1.5 port 3 down", Host: "192.168.1.5"},
+		{Text: "10:15:20 2001:db8::5 port 3 down", Host: "2001:db8::5"},
+		{Text: "at 10:15:20 2001:db8:0:0:1:2:3:4 port 3 down", Host: "2001:db8::1:2:3:4"},
+		{Text: "::ffff:192.168.1.5 port 3 down", Host: "192.168.1.5"},
+		{Text: "fe80:: port 3 down", Host: "fe80::"},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.131")
+		if err != nil {
+			t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+		}
+		if event.Host != tt.Host || event.Port != 3 {
+			t.Fatal("unexpected result - parse result not match with criterias", tt.Text, event)
+		}
+	}
+}
+
+func TestParserEventTypes(t *testing.T) {
+	p, err := parser.NewParser([]string{
+		"stp_topology_change ~ topology change on port $device_port$",
+		"link_down ~ port $device_port$ link down",
+		`psu_failure ~re~ ^Power supply %{INT} failed$`,
+	}, parser.Options{
+		EventTypes: []parser.EventType{
+			{Name: "stp_topology_change", Description: "STP topology change", Severity: 5},
+			{Name: "psu_failure", Description: "power supply failure", Severity: 2},
+			{Name: "link_down", Description: "port link is down", Severity: 3},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text     string
+		Type     pb.EventType
+		TypeName string
+		Severity uint32
+	}{
+		{Text: "topology change on port 5", Type: pb.EventType_Unknown, TypeName: "stp_topology_change", Severity: 5},
+		{Text: "Power supply 2 failed", Type: pb.EventType_Unknown, TypeName: "psu_failure", Severity: 2},
+		// Тип из конфигурации заменяет уровень важности встроенного типа.
+		{Text: "port 6 link down", Type: pb.EventType_PortDown, TypeName: "link_down", Severity: 3},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.130")
+		if err != nil {
+			t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+		}
+		if event.Type != tt.Type || event.TypeName != tt.TypeName || event.Severity != tt.Severity {
+			t.Fatal("unexpected result - event type not match with criterias", tt.Text, event)
+		}
+	}
+
+	// Неизвестный тип события шаблона и повторное определение типа.
+	if _, err := parser.NewParser([]string{"fan_failure ~ fan $ignore$ failed"}, parser.Options{}); err == nil {
+		t.Fatal("unexpected result - unknown event type is accepted")
+	}
+	_, err = parser.NewParser([]string{"psu_failure ~ psu $ignore$ failed"}, parser.Options{
+		EventTypes: []parser.EventType{{Name: "psu_failure"}, {Name: "psu_failure"}},
+	})
+	if err == nil {
+		t.Fatal("unexpected result - duplicated event type is accepted")
+	}
+}
+
+func TestParserAttributes(t *testing.T) {
+	opts := parser.Options{
+		Fields: map[string]string{
+			"vlan":   "int",
+			"mac":    "mac",
+			"peer":   "ip",
+			"uptime": "duration",
+		},
+	}
+	p, err := parser.NewParser([]string{
+		"link_down ~ port $device_port$ vlan $vlan$ down by $user$ after $uptime$",
+		"ignore ~ $ignore$ learned $mac$ on port $device_port$",
+		`link_up ~re~ ^port (?P<device_port>\S+) up, neighbor (?P<peer>%{IP}) \((?P<neighbor>.+)\)$`,
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text       string
+		Attributes map[string]string
+		OK         bool
+	}{
+		{
+			Text:       "port 3 vlan 0100 down by admin after 3600",
+			Attributes: map[string]string{"vlan": "100", "user": "admin", "uptime": "1h0m0s"},
+			OK:         true,
+		},
+		{
+			Text:       "fdb learned 0011.22AA.bbcc on port 4",
+			Attributes: map[string]string{"mac": "00:11:22:aa:bb:cc"},
+			OK:         true,
+		},
+		{
+			Text:       "port eth1/5 up, neighbor 10.0.0.1 (core switch 1)",
+			Attributes: map[string]string{"peer": "10.0.0.1", "neighbor": "core switch 1"},
+			OK:         true,
+		},
+		{
+			Text: "port 3 vlan default down by admin after 1h",
+			OK:   false,
+		},
+		{
+			Text: "fdb learned 00:11:22 on port 4",
+			OK:   false,
+		},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.140")
+		if err != nil {
+			if _, ok := err.(*parser.ErrDataParse); !ok && !tt.OK {
+				t.Fatal("unexpected result - conversion err expected", tt.Text, err)
+			}
+			if tt.OK {
+				t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+			}
+			continue
+		}
+		if !tt.OK || fmt.Sprint(event.Attributes) != fmt.Sprint(tt.Attributes) {
+			t.Fatal("unexpected result - attributes not match with criterias", tt.Text, event.Attributes)
+		}
+	}
+
+	// Неизвестный тип поля и изменение типа встроенного поля.
+	for _, fields := range []map[string]string{{"vlan": "float"}, {"device_port": "int"}} {
+		if _, err := parser.NewParser([]string{"link_down ~ port $device_port$ vlan $vlan$"}, parser.Options{Fields: fields}); err == nil {
+			t.Fatal("unexpected result - invalid field type is accepted", fields)
+		}
+	}
+}
+
+func TestParserPortSpeed(t *testing.T) {
+	p, err := parser.NewParser([]string{
+		"link_up ~ port $device_port$ up $port_speed$",
+		`link_up ~re~ ^interface (?P<device_port>\S+) up, speed %{SPEED:port_speed}$`,
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text  string
+		Speed pb.PortSpeed
+		Mbps  uint64
+		OK    bool
+	}{
+		{Text: "port 1 up 100mb", Speed: pb.PortSpeed_Speed100Mb, Mbps: 100, OK: true},
+		{Text: "port 1 up 1000", Speed: pb.PortSpeed_Speed1Gb, Mbps: 1000, OK: true},
+		{Text: "port 1 up 2.5G", Speed: pb.PortSpeed_Speed2500Mb, Mbps: 2500, OK: true},
+		{Text: "port 1 up 10G", Speed: pb.PortSpeed_Speed10Gb, Mbps: 10000, OK: true},
+		{Text: "port 1 up 25Gb/s", Speed: pb.PortSpeed_Speed25Gb, Mbps: 25000, OK: true},
+		{Text: "port 1 up 40Gbps", Speed: pb.PortSpeed_Speed40Gb, Mbps: 40000, OK: true},
+		{Text: "port 1 up 100000Mbps", Speed: pb.PortSpeed_Speed100Gb, Mbps: 100000, OK: true},
+		{Text: "port 1 up 400G", Speed: pb.PortSpeed_Speed400Gb, Mbps: 400000, OK: true},
+		{Text: "port 1 up 1.2T", Speed: pb.PortSpeed_UnknownSpeed, Mbps: 1200000, OK: true},
+		{Text: "port 1 up auto", Speed: pb.PortSpeed_SpeedAuto, OK: true},
+		{Text: "port 1 up Auto-negotiation", Speed: pb.PortSpeed_SpeedAuto, OK: true},
+		{Text: "port 1 up unknown", Speed: pb.PortSpeed_UnknownSpeed, OK: true},
+		{Text: "interface Te1/0/1 up, speed 10 Gbps", Speed: pb.PortSpeed_Speed10Gb, Mbps: 10000, OK: true},
+		{Text: "interface Hu1/0/49 up, speed 100G", Speed: pb.PortSpeed_Speed100Gb, Mbps: 100000, OK: true},
+		{Text: "interface Gi1/0/1 up, speed auto", Speed: pb.PortSpeed_SpeedAuto, OK: true},
+		{Text: "port 1 up fast", OK: false},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.150")
+		if err != nil {
+			if tt.OK {
+				t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+			}
+			continue
+		}
+		if !tt.OK || event.Speed != tt.Speed || event.SpeedMbps != tt.Mbps {
+			t.Fatal("unexpected result - port speed not match with criterias", tt.Text, event.Speed, event.SpeedMbps)
+		}
+	}
+}
+
+func TestParserInterfaces(t *testing.T) {
+	p, err := parser.NewParser([]string{
+		"link_down ~ interface $device_port$ down",
+	}, parser.Options{
+		Interfaces: map[string]string{"ge": "GigabitEthernet"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Text      string
+		Port      uint32
+		Interface string
+		ID        *pb.InterfaceID
+	}{
+		{
+			Text:      "interface Ethernet1/0/3 down",
+			Port:      3,
+			Interface: "Ethernet1/0/3",
+			ID:        &pb.InterfaceID{Type: "Ethernet", Slot: 1, Module: 0, Port: 3},
+		},
+		{
+			Text:      "interface Ethernet2/0/3 down",
+			Port:      3,
+			Interface: "Ethernet2/0/3",
+			ID:        &pb.InterfaceID{Type: "Ethernet", Slot: 2, Module: 0, Port: 3},
+		},
+		{
+			Text:      "interface Po3 down",
+			Port:      3,
+			Interface: "Port-channel3",
+			ID:        &pb.InterfaceID{Type: "Port-channel", Port: 3},
+		},
+		{
+			Text:      "interface Gi1/0/12 down",
+			Port:      12,
+			Interface: "GigabitEthernet1/0/12",
+			ID:        &pb.InterfaceID{Type: "GigabitEthernet", Slot: 1, Port: 12},
+		},
+		{
+			Text:      "interface te0/1.100 down",
+			Port:      100,
+			Interface: "TenGigabitEthernet0/1.100",
+			ID:        &pb.InterfaceID{Type: "TenGigabitEthernet", Slot: 0, Port: 1},
+		},
+		{
+			Text:      "interface ge-0/0/5 down",
+			Port:      5,
+			Interface: "GigabitEthernet0/0/5",
+			ID:        &pb.InterfaceID{Type: "GigabitEthernet", Port: 5},
+		},
+		{
+			Text:      "interface xe-1/2/3 down",
+			Port:      3,
+			Interface: "xe-1/2/3",
+			ID:        &pb.InterfaceID{Type: "xe", Slot: 1, Module: 2, Port: 3},
+		},
+		{
+			Text:      "interface 7 down",
+			Port:      7,
+			Interface: "7",
+			ID:        &pb.InterfaceID{Port: 7},
+		},
+	}
+	for _, tt := range tests {
+		event, err := p.Parse(tt.Text, "192.168.1.160")
+		if err != nil {
+			t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+		}
+		if event.Port != tt.Port || event.Interface != tt.Interface || event.InterfaceID.String() != tt.ID.String() {
+			t.Fatal("unexpected result - interface not match with criterias", tt.Text, event.Port, event.Interface, event.InterfaceID)
+		}
+	}
+}
+
+func TestParserRecord(t *testing.T) {
+	jp, err := parser.New("json", []string{
+		"link_down ~ event=link state=down host=$device_addr$ port=$device_port$",
+		"link_up ~ event=link state=up port=$device_port$ speed.value=$port_speed$ duplex=$port_duplex$ vlan=$vlan$",
+	}, parser.Options{Fields: map[string]string{"vlan": "int"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kp, err := parser.New("kv", []string{
+		"link_down ~ action=down ifname=$device_port$ user=$user$",
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Parser parser.Parser
+		Text   string
+		Event  *pb.Event
+		Attr   map[string]string
+		OK     bool
+	}{
+		{
+			Parser: jp,
+			Text:   `{"event": "link", "state": "down", "host": "192.168.1.170", "port": "Gi1/0/4"}`,
+			Event:  &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.170", Port: 4},
+			OK:     true,
+		},
+		{
+			Parser: jp,
+			Text:   `@cee: {"event": "link", "state": "up", "port": 5, "speed": {"value": "10G"}, "duplex": "full", "vlan": 10}`,
+			Event:  &pb.Event{Type: pb.EventType_PortUp, Host: "192.168.1.1", Port: 5, Speed: pb.PortSpeed_Speed10Gb, Duplex: pb.PortDuplex_Full},
+			Attr:   map[string]string{"vlan": "10"},
+			OK:     true,
+		},
+		{
+			Parser: jp,
+			Text:   `{"event": "link", "state": "testing", "port": 5}`,
+			OK:     false,
+		},
+		{
+			Parser: jp,
+			Text:   `port 5 link down`,
+			OK:     false,
+		},
+		{
+			Parser: kp,
+			Text:   `lldpd: action=down ifname=eth0/6 user="root admin" reason=carrier`,
+			Event:  &pb.Event{Type: pb.EventType_PortDown, Host: "192.168.1.1", Port: 6},
+			Attr:   map[string]string{"user": "root admin"},
+			OK:     true,
+		},
+		{
+			Parser: kp,
+			Text:   `action=down user=root`,
+			OK:     false,
+		},
+	}
+	for _, tt := range tests {
+		event, err := tt.Parser.Parse(tt.Text, "192.168.1.1")
+		if err != nil {
+			if tt.OK {
+				t.Fatal("unexpected result - failed to parse normal message", tt.Text, err)
+			}
+			continue
+		}
+		if !tt.OK || !sameEvent(event, tt.Event) || fmt.Sprint(event.Attributes) != fmt.Sprint(tt.Attr) {
+			t.Fatal("unexpected result - parse result not match with criterias", tt.Text, event)
+		}
+	}
+}
+
+func TestParserRecordUnknownFormat(t *testing.T) {
+	jp, err := parser.New("json", []string{
+		"link_down ~ event=link state=down port=$device_port$",
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kp, err := parser.New("kv", []string{
+		"link_down ~ action=down ifname=$device_port$",
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{`port 5 link down`, `{"event": "link"`} {
+		if _, err := jp.Parse(text, "192.168.1.1"); err == nil {
+			t.Fatal("unexpected result - json parser accepts message", text)
+		} else if _, ok := err.(*parser.ErrUnknownFormat); !ok {
+			t.Fatal("unexpected result - json parse error is not unknown format", text, err)
+		}
+	}
+	for _, text := range []string{`port 5 link down`, `action=down reason="carrier`} {
+		if _, err := kp.Parse(text, "192.168.1.1"); err == nil {
+			t.Fatal("unexpected result - kv parser accepts message", text)
+		} else if _, ok := err.(*parser.ErrUnknownFormat); !ok {
+			t.Fatal("unexpected result - kv parse error is not unknown format", text, err)
+		}
+	}
+	// Ошибка преобразования поля совпавшего шаблона остается ошибкой уровня обработки.
+	if _, err := jp.Parse(`{"event": "link", "state": "down", "port": "X"}`, "192.168.1.1"); err == nil {
+		t.Fatal("unexpected result - json parser accepts invalid port")
+	} else if _, ok := err.(*parser.ErrUnknownFormat); ok {
+		t.Fatal("unexpected result - field parse error is unknown format", err)
+	}
+
+	// Сообщение не в формате JSON передается следующему набору шаблонов.
+	tp, err := parser.NewParser([]string{"link_down ~ port $device_port$ link down"}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := parser.NewScoped([]parser.Scope{{Name: "json", Parser: jp}, {Name: "text", Parser: tp}}, nil)
+	event, err := sp.Parse("port 5 link down", "192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != pb.EventType_PortDown || event.Port != 5 {
+		t.Fatal("unexpected result - parse result not match with criterias", event)
+	}
+}
+
+func TestParserStats(t *testing.T) {
+	templates := []string{
+		"link_down [id=down] ~ port $device_port$ link down",
+		`link_up [id=up] ~re~ ^port (?P<device_port>\d+) link up$`,
+	}
+	texts := []string{"port 5 link down", "port 6 link up", "power supply failed"}
+	// Текстовый шаблон отбирается по словам сообщения (или по их количеству - Linear),
+	// регулярное выражение сверяется с каждым сообщением, не совпавшим с текстовым шаблоном.
+	tests := []struct {
+		Linear bool
+		Text   [2]uint64
+		Regex  [2]uint64
+	}{
+		{Linear: false, Text: [2]uint64{1, 0}, Regex: [2]uint64{1, 1}},
+		{Linear: true, Text: [2]uint64{1, 1}, Regex: [2]uint64{1, 1}},
+	}
+	for _, tt := range tests {
+		p, err := parser.NewParser(templates, parser.Options{Linear: tt.Linear})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, text := range texts {
+			p.Parse(text, "192.168.1.1")
+		}
+		stats := p.Stats()
+		if len(stats) != 2 || stats[0].ID != "down" || stats[1].ID != "up" ||
+			[2]uint64{stats[0].Hits, stats[0].Misses} != tt.Text || [2]uint64{stats[1].Hits, stats[1].Misses} != tt.Regex ||
+			stats[0].Errors != 0 || stats[1].Errors != 0 {
+			t.Fatal("unexpected result - template stats not match with criterias", tt.Linear, stats)
+		}
+	}
+}
+
+func TestParserRegistry(t *testing.T) {
+	if fmt.Sprint(parser.Kinds()) != "[json kv template]" {
+		t.Fatal("unexpected result - registered kinds not match with criterias", parser.Kinds())
+	}
+	if _, err := parser.New("xml", patterns, parser.Options{}); err == nil {
+		t.Fatal("unexpected result - unknown parser kind is accepted")
+	}
+	p, err := parser.New("", patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Parse("192.168.1.105 - - - port 7 change link state to down", ""); err != nil {
+		t.Fatal("unexpected result - default parser kind failed", err)
+	}
+}
+
+// mockCorpus - шаблоны и сообщения cmd/mock вместе с n синтетическими шаблонами оборудования.
+func mockCorpus(n, size int) ([]string, []string) {
+	templates := append(mock.VendorPatterns(n), mock.Patterns...)
+	gen := mock.NewGenerator(1)
+	corpus := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		if i%2 == 0 {
+			corpus = append(corpus, gen.Message())
+		} else {
+			corpus = append(corpus, gen.VendorMessage(n))
+		}
+	}
+	return templates, corpus
+}
+
+func TestParserIndex(t *testing.T) {
+	templates, corpus := mockCorpus(300, 2000)
+	// Шаблоны с литералом и полем данных в одной позиции, порядок указания сохраняется.
+	templates = append([]string{
+		"ignore ~ $device_addr$ - - - port 1 change link state to down",
+		"link_down ~ $device_addr$ - - - port $device_port$ change link state to $plain_text$",
+	}, templates...)
+	linear, err := parser.NewParser(templates, parser.Options{Linear: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexed, err := parser.NewParser(templates, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := 0
+	for _, msg := range corpus {
+		a, errA := linear.Parse(msg, "")
+		b, errB := indexed.Parse(msg, "")
+		if (errA == nil) != (errB == nil) || (errA == nil && !sameEvent(a, b)) {
+			t.Fatal("unexpected result - indexed and linear results not match", msg, a, b, errA, errB)
+		}
+		if errA == nil {
+			matched++
+		}
+	}
+	if matched < len(corpus)/2 {
+		t.Fatal("unexpected result - too few messages matched", matched)
+	}
+}
+
+func benchmarkParser(b *testing.B, n int, linear bool) {
+	templates, corpus := mockCorpus(n, 1000)
+	p, err := parser.NewParser(templates, parser.Options{Linear: linear})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Parse(corpus[i%len(corpus)], "")
+	}
+}
+
+func BenchmarkParserLinear10(b *testing.B)   { benchmarkParser(b, 10, true) }
+func BenchmarkParserLinear100(b *testing.B)  { benchmarkParser(b, 100, true) }
+func BenchmarkParserLinear1000(b *testing.B) { benchmarkParser(b, 1000, true) }
+func BenchmarkParserIndex10(b *testing.B)    { benchmarkParser(b, 10, false) }
+func BenchmarkParserIndex100(b *testing.B)   { benchmarkParser(b, 100, false) }
+func BenchmarkParserIndex1000(b *testing.B)  { benchmarkParser(b, 1000, false) }
+
+func TestParserPriority(t *testing.T) {
+	cases := []struct {
+		Templates []string
+		Text      string
+		Type      string
+		OK        bool
+	}{
+		// Приоритет важнее порядка указания.
+		{
+			Templates: []string{
+				"ignore ~ port $device_port$ down",
+				"link_down [priority=5] ~ port $device_port$ down",
+			},
+			Text: "port 5 down",
+			Type: "link_down",
+			OK:   true,
+		},
+		// Регулярное выражение с большим приоритетом сверяется раньше текстового шаблона.
+		{
+			Templates: []string{
+				"ignore ~ port $device_port$ down",
+				"link_down [priority=1] ~re~ ^port (?P<device_port>\\d+) down$",
+			},
+			Text: "port 5 down",
+			Type: "link_down",
+			OK:   true,
+		},
+		// Ошибка данных прекращает разбор (on_error=stop по умолчанию).
+		{
+			Templates: []string{
+				"link_down ~ port $device_port$ down",
+				"ignore ~ port $ignore$ down",
+			},
+			Text: "port X down",
+			OK:   false,
+		},
+		// on_error=next - сверка продолжается со следующими шаблонами.
+		{
+			Templates: []string{
+				"link_down [on_error=next] ~ port $device_port$ down",
+				"ignore ~ port $ignore$ down",
+			},
+			Text: "port X down",
+			Type: "ignore",
+			OK:   true,
+		},
+		// При отсутствии совпадений возвращается первая ошибка данных.
+		{
+			Templates: []string{
+				"link_down [on_error=next] ~ port $device_port$ down",
+				"link_up ~ port $ignore$ up",
+			},
+			Text: "port X down",
+			OK:   false,
+		},
+	}
+	for _, linear := range []bool{false, true} {
+		for _, c := range cases {
+			p, err := parser.NewParser(c.Templates, parser.Options{Linear: linear})
+			if err != nil {
+				t.Fatal(err)
+			}
+			event, err := p.Parse(c.Text, "10.0.0.1")
+			if (err == nil) != c.OK {
+				t.Fatal("unexpected result - parse result not match with criterias", c.Templates, err)
+			}
+			if c.OK && event.TypeName != c.Type {
+				t.Fatal("unexpected result - event type not match with criterias", c.Templates, event)
+			}
+		}
+	}
+
+	for _, v := range []string{
+		"link_down [priority=high] ~ port $device_port$ down",
+		"link_down [on_error=skip] ~ port $device_port$ down",
+		"link_down [weight=1] ~ port $device_port$ down",
+		"link_down [priority] ~ port $device_port$ down",
+		"link_down [priority=1 ~ port $device_port$ down",
+	} {
+		if _, err := parser.NewParser([]string{v}, parser.Options{}); err == nil {
+			t.Fatal("unexpected result - invalid template options are accepted", v)
+		}
+	}
+}
+
+func TestParserShadowed(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	_, err := parser.NewParser([]string{
+		"link_down ~ port $device_port$ down",
+		"ignore ~ port 1 down",
+		"link_up [on_error=next] ~ port $device_port$ up",
+		"ignore ~ port 1 up",
+		"loopdetect [priority=1] ~ port $ignore$ loop",
+		"link_down ~ port $device_port$ loop",
+		`link_down ~re~ ^Interface (?P<device_port>\S+) down$`,
+		`link_up [priority=1] ~re~ ^Interface (?P<device_port>\S+) down$`,
+		`link_down [on_error=next] ~re~ ^Interface (?P<device_port>\S+) up$`,
+		`link_up ~re~ ^Interface (?P<device_port>\S+) up$`,
+	}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	if len(warnings) != 3 ||
+		!strings.Contains(warnings[0], "#2") || !strings.Contains(warnings[0], "#1") ||
+		!strings.Contains(warnings[1], "#6") || !strings.Contains(warnings[1], "#5") ||
+		!strings.Contains(warnings[2], "#7") || !strings.Contains(warnings[2], "#8") {
+		t.Fatal("unexpected result - shadowed templates warnings not match with criterias", warnings)
+	}
+}
+
+func TestParserTemplateID(t *testing.T) {
+	templates := []string{
+		"link_down ~ port $device_port$ down",
+		"link_up [priority=2] ~re~ ^port (?P<device_port>\\d+) up speed (?P<port_speed>\\S+)$",
+		"ignore [id=port-ignore] ~ port $device_port$ ignored",
+	}
+	ids := func(templates []string) map[string]string {
+		p, err := parser.NewParser(templates, parser.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make(map[string]string)
+		for _, text := range []string{"port X down", "port 1 up speed fast", "port X ignored"} {
+			_, err := p.Parse(text, "")
+			e, ok := err.(*parser.ErrDataParse)
+			if !ok || len(e.Template) == 0 {
+				t.Fatal("unexpected result - data error without template id", text, err)
+			}
+			result[text] = e.Template
+		}
+		return result
+	}
+	a := ids(templates)
+	if a["port X ignored"] != "port-ignore" || a["port X down"] == a["port 1 up speed fast"] {
+		t.Fatal("unexpected result - template ids not match with criterias", a)
+	}
+	// Идентификатор не зависит от порядка шаблонов и прочих параметров.
+	b := ids([]string{
+		templates[2],
+		"link_up ~re~ ^port (?P<device_port>\\d+) up speed (?P<port_speed>\\S+)$",
+		"link_down [on_error=next] ~ port $device_port$ down",
+	})
+	for k, v := range a {
+		if b[k] != v {
+			t.Fatal("unexpected result - template ids are not stable", a, b)
+		}
+	}
+
+	for _, v := range [][]string{
+		{"link_down [id=a] ~ port $device_port$ down", "link_up [id=a] ~ port $device_port$ up"},
+		{"link_down [id=a/b] ~ port $device_port$ down"},
+	} {
+		if _, err := parser.NewParser(v, parser.Options{}); err == nil {
+			t.Fatal("unexpected result - invalid template ids are accepted", v)
+		}
+	}
+}
@@ -0,0 +1,594 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+)
+
+var (
+	headerMessages = []struct {
+		Text   string
+		Host   string
+		Port   uint32
+		Header *pb.SyslogHeader
+	}{
+		{
+			Text: "<165>1 2003-10-11T22:14:15.003Z switch01.example.com linkd - LINK [exampleSDID@32473 iut=\"3\" eventSource=\"Application\"] 192.168.1.105 - - - port 7 change link state to down",
+			Host: "192.168.1.105",
+			Port: 7,
+			Header: &pb.SyslogHeader{
+				Priority:  165,
+				Facility:  20,
+				Severity:  5,
+				Timestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC).UnixNano(),
+				Hostname:  "switch01.example.com",
+				AppName:   "linkd",
+				MsgID:     "LINK",
+			},
+		},
+		{
+			Text: "<34>1 - - - 1234 - - 192.168.1.106 - - - port 8 change link state to down",
+			Host: "192.168.1.106",
+			Port: 8,
+			Header: &pb.SyslogHeader{
+				Priority: 34,
+				Facility: 4,
+				Severity: 2,
+				ProcID:   "1234",
+			},
+		},
+		{
+			Text: "192.168.1.107 - - - port 9 change link state to down",
+			Host: "192.168.1.107",
+			Port: 9,
+		},
+	}
+)
+
+// listenEvents - запустить обработчик на указанном адресе и отправить в него сообщения.
+func listenEvents(t *testing.T, addr string, opts syslog.Options, texts ...string) []*pb.Event {
+	return listenTemplateEvents(t, addr, opts, patterns, texts...)
+}
+
+// listenTemplateEvents - аналогично listenEvents, с указанным набором шаблонов.
+func listenTemplateEvents(t *testing.T, addr string, opts syslog.Options, templates []string, texts ...string) []*pb.Event {
+	p, err := parser.NewParser(templates, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lsn, err := syslog.NewListener(addr, opts, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	result := make([]*pb.Event, 0)
+	for _, text := range texts {
+		if _, err := conn.Write([]byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case event := <-ch:
+			result = append(result, event)
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - no event for message", text)
+		}
+	}
+
+	return result
+}
+
+func TestListenerHeaderRFC3164(t *testing.T) {
+	texts := []string{
+		"<13>Jun 15 11:00:00 switch02 linkd[512]: 192.168.1.105 - - - port 7 change link state to down",
+		"<14>Jun 18 11:00:00 10.0.0.1 192.168.1.106 - - - port 8 change link state to down",
+	}
+	expected := []*pb.SyslogHeader{
+		{
+			Priority:  13,
+			Facility:  1,
+			Severity:  5,
+			Timestamp: time.Date(2023, 6, 15, 11, 0, 0, 0, time.UTC).UnixNano(),
+			Hostname:  "switch02",
+			AppName:   "linkd",
+			ProcID:    "512",
+		},
+		{
+			Priority:  14,
+			Facility:  1,
+			Severity:  6,
+			Timestamp: time.Date(2022, 6, 18, 11, 0, 0, 0, time.UTC).UnixNano(),
+			Hostname:  "10.0.0.1",
+		},
+	}
+	now := func() time.Time { return time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC) }
+	events := listenEvents(t, "127.0.0.1:55515", syslog.Options{BufSize: 1500, Location: time.UTC, Now: now}, texts...)
+	for k, event := range events {
+		if event.Header == nil || *event.Header != *expected[k] {
+			t.Fatal("unexpected result - header not match with criterias", texts[k], event.Header)
+		}
+	}
+}
+
+func TestListenerHeaderRFC3164Year(t *testing.T) {
+	// Год сообщения определяется по текущему времени сервиса, в том числе при переходе через новый год.
+	cases := []struct {
+		Now   time.Time
+		Stamp string
+		Time  time.Time
+	}{
+		{
+			Now:   time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC),
+			Stamp: "Dec 31 23:59:50",
+			Time:  time.Date(2023, 12, 31, 23, 59, 50, 0, time.UTC),
+		},
+		{
+			Now:   time.Date(2023, 12, 31, 23, 59, 50, 0, time.UTC),
+			Stamp: "Jan  1 00:00:05",
+			Time:  time.Date(2024, 1, 1, 0, 0, 5, 0, time.UTC),
+		},
+		{
+			Now:   time.Date(2023, 12, 31, 23, 59, 50, 0, time.UTC),
+			Stamp: "Dec 31 23:59:40",
+			Time:  time.Date(2023, 12, 31, 23, 59, 40, 0, time.UTC),
+		},
+		{
+			Now:   time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+			Stamp: "Jan  3 08:00:00",
+			Time:  time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			Now:   time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Stamp: "Mar  5 12:00:00",
+			Time:  time.Date(2023, 3, 5, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, c := range cases {
+		now := c.Now
+		text := "<13>" + c.Stamp + " switch02 192.168.1.105 - - - port 7 change link state to down"
+		opts := syslog.Options{BufSize: 1500, Location: time.UTC, Now: func() time.Time { return now }}
+		events := listenEvents(t, "127.0.0.1:55515", opts, text)
+		if events[0].Header == nil || events[0].Header.Timestamp != c.Time.UnixNano() {
+			t.Fatal("unexpected result - timestamp not match with criterias", c.Now, text, events[0].Header)
+		}
+	}
+}
+
+func TestListenerHeader(t *testing.T) {
+	texts := make([]string, 0)
+	for _, msg := range headerMessages {
+		texts = append(texts, msg.Text)
+	}
+	events := listenEvents(t, "127.0.0.1:55514", syslog.Options{BufSize: 1500, Location: time.UTC}, texts...)
+	for k, msg := range headerMessages {
+		event := events[k]
+		if event.Host != msg.Host || event.Port != msg.Port {
+			t.Fatal("unexpected result - parse result not match with criterias", msg.Text, event)
+		}
+		if msg.Header == nil {
+			if event.Header != nil {
+				t.Fatal("unexpected result - header for message without header", msg.Text, event.Header)
+			}
+			continue
+		}
+		if event.Header == nil || *event.Header != *msg.Header {
+			t.Fatal("unexpected result - header not match with criterias", msg.Text, event.Header)
+		}
+	}
+}
+
+func TestTCPListener(t *testing.T) {
+	p, err := parser.NewParser(patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:55516"
+	lsn, err := syslog.NewTCPListener(addr, syslog.Options{BufSize: 1500, IdleTimeout: time.Second, Location: time.UTC}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Сообщения с разными способами разделения в одном потоке:
+	// octet counting, LF, CRLF и сообщение, превышающее размер буфера.
+	msg := "<34>1 - - - - - - 192.168.1.105 - - - port 7 change link state to down"
+	stream := fmt.Sprintf("%d %s", len(msg), msg) +
+		"192.168.1.106 - - - port 8 change link state to down\n" +
+		"<13>Oct 11 22:14:15 switch01 192.168.1.107 - - - port 9 change link state to down\r\n" +
+		fmt.Sprintf("%d %s", 2000, strings.Repeat("x", 2000)) +
+		"192.168.1.108 - - - port 10 change link state to down\n"
+	if _, err := conn.Write([]byte(stream)); err != nil {
+		t.Fatal(err)
+	}
+	for _, port := range []uint32{7, 8, 9, 10} {
+		select {
+		case event := <-ch:
+			if event.Port != port {
+				t.Fatal("unexpected result - parse result not match with criterias", port, event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - no event for port", port)
+		}
+	}
+
+	// Соединение закрывается по истечении времени бездействия.
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatal("unexpected result - idle connection is not closed", err)
+	}
+}
+
+func TestListenerSourceAddr(t *testing.T) {
+	templates := []string{
+		"link_down ~ $device_addr$ - - - port $device_port$ change link state to down",
+		"link_down ~ port $device_port$ change link state to down",
+	}
+	texts := []string{
+		"192.168.1.105 - - - port 7 change link state to down",
+		"port 8 change link state to down",
+	}
+
+	// Адрес из сообщения имеет приоритет, адрес отправителя пакета - если адреса в сообщении нет.
+	events := listenTemplateEvents(t, "127.0.0.1:55518", syslog.Options{BufSize: 1500}, templates, texts...)
+	if events[0].Host != "192.168.1.105" || events[1].Host != "127.0.0.1" {
+		t.Fatal("unexpected result - host not match with criterias", events)
+	}
+
+	// Адрес отправителя пакета имеет приоритет.
+	events = listenTemplateEvents(t, "127.0.0.1:55519", syslog.Options{BufSize: 1500, PreferSource: true}, templates, texts...)
+	if events[0].Host != "127.0.0.1" || events[1].Host != "127.0.0.1" {
+		t.Fatal("unexpected result - host not match with criterias", events)
+	}
+}
+
+func TestListenerWorkers(t *testing.T) {
+	p, err := parser.NewParser(patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:55520"
+	lsn, err := syslog.NewListener(addr, syslog.Options{BufSize: 1500, Workers: 4, QueueSize: 256}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Сообщения одного отправителя передаются в порядке получения.
+	count := 200
+	for k := 1; k <= count; k++ {
+		fmt.Fprintf(conn, "192.168.1.105 - - - port %d change link state to down", k)
+	}
+	for k := 1; k <= count; k++ {
+		select {
+		case event := <-ch:
+			if event.Port != uint32(k) {
+				t.Fatal("unexpected result - events are reordered", k, event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - no event for port", k)
+		}
+	}
+	if c := lsn.Counters(); c.Recv != uint64(count) || c.Parsed != uint64(count) || c.Dropped != 0 {
+		t.Fatal("unexpected result - counters not match with criterias", c)
+	}
+}
+
+func TestListenerOverflow(t *testing.T) {
+	p, err := parser.NewParser(patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:55521"
+	lsn, err := syslog.NewListener(addr, syslog.Options{BufSize: 1500, Workers: 1, QueueSize: 4}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	// События не считываются из канала - очередь переполняется.
+	go lsn.Listen(make(chan *pb.Event))
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	count := 50
+	for k := 1; k <= count; k++ {
+		fmt.Fprintf(conn, "192.168.1.105 - - - port %d change link state to down", k)
+	}
+	deadline := time.Now().Add(time.Second)
+	for lsn.Counters().Recv < uint64(count) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c := lsn.Counters()
+	if c.Recv != uint64(count) || c.Dropped == 0 || c.Parsed+c.Dropped > c.Recv {
+		t.Fatal("unexpected result - counters not match with criterias", c)
+	}
+}
+
+// dialSources - открыть соединения с адресов 127.0.0.2 ... 127.0.0.(sources+1).
+func dialSources(t testing.TB, addr string, sources int) []*net.UDPConn {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := make([]*net.UDPConn, sources)
+	for k := range conns {
+		conns[k], err = net.DialUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, byte((k+2)>>8), byte(k+2))}, raddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conns
+}
+
+// sendFromSources - отправить count сообщений через каждое из соединений и закрыть их.
+func sendFromSources(conns []*net.UDPConn, count int) {
+	wg := sync.WaitGroup{}
+	wg.Add(len(conns))
+	for _, conn := range conns {
+		go func(conn *net.UDPConn) {
+			defer wg.Done()
+			defer conn.Close()
+			for n := 1; n <= count; n++ {
+				fmt.Fprintf(conn, "port %d change link state to down", n)
+			}
+		}(conn)
+	}
+	wg.Wait()
+}
+
+func TestListenerReusePort(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT listener is supported on linux only")
+	}
+	p, err := parser.NewParser([]string{"link_down ~ port $device_port$ change link state to down"}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:55522"
+	lsn, err := syslog.NewListener(addr, syslog.Options{BufSize: 1500, Sockets: 4, Batch: 16, ReadBuffer: 1 << 20}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+
+	sources, count := 8, 50
+	go sendFromSources(dialSources(t, addr, sources), count)
+
+	// Сообщения каждого отправителя передаются в порядке получения.
+	last := make(map[string]uint32)
+	for k := 0; k < sources*count; k++ {
+		select {
+		case event := <-ch:
+			if event.Port != last[event.Host]+1 {
+				t.Fatal("unexpected result - events are reordered", last[event.Host], event)
+			}
+			last[event.Host] = event.Port
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - not all events are received", lsn.Counters())
+		}
+	}
+	if len(last) != sources {
+		t.Fatal("unexpected result - sources not match with criterias", last)
+	}
+	if c := lsn.Counters(); c.Recv != uint64(sources*count) || c.KernelDropped != 0 {
+		t.Fatal("unexpected result - counters not match with criterias", c)
+	}
+}
+
+// benchmarkListener - измерить время обработки сообщений от нескольких отправителей.
+func benchmarkListener(b *testing.B, addr string, opts syslog.Options) {
+	p, err := parser.NewParser([]string{"link_down ~ port $device_port$ change link state to down"}, parser.Options{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	lsn, err := syslog.NewListener(addr, opts, p)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event, 1024)
+	go lsn.Listen(ch)
+	go func() {
+		for range ch {
+		}
+	}()
+
+	sources := 8
+	total := uint64(b.N/sources+1) * uint64(sources)
+	conns := dialSources(b, addr, sources)
+	b.ResetTimer()
+	sendFromSources(conns, b.N/sources+1)
+	// Ожидание обработки всех сообщений, либо прекращения приема (часть сообщений отброшена ядром).
+	for last, idle := uint64(0), 0; idle < 50; idle++ {
+		c := lsn.Counters()
+		if c.Recv+c.KernelDropped >= total {
+			break
+		}
+		if c.Recv != last {
+			last, idle = c.Recv, 0
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.StopTimer()
+	c := lsn.Counters()
+	b.ReportMetric(float64(c.Recv)/float64(total), "recv/msg")
+	b.ReportMetric(float64(c.KernelDropped)/float64(total), "kernel-drops/msg")
+	b.ReportMetric(float64(c.Dropped)/float64(total), "queue-drops/msg")
+}
+
+func BenchmarkListenerSingle(b *testing.B) {
+	benchmarkListener(b, "127.0.0.1:55523", syslog.Options{BufSize: 1500, ReadBuffer: 4 << 20})
+}
+
+func BenchmarkListenerReusePortBatch(b *testing.B) {
+	if runtime.GOOS != "linux" {
+		b.Skip("SO_REUSEPORT listener is supported on linux only")
+	}
+	benchmarkListener(b, "127.0.0.1:55524", syslog.Options{BufSize: 1500, ReadBuffer: 4 << 20, Sockets: 4, Batch: 32})
+}
+
+func TestListenerRateLimit(t *testing.T) {
+	p, err := parser.NewParser([]string{"link_down ~ port $device_port$ change link state to down"}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		Addr       string
+		Limit      syslog.RateLimit
+		Passed     int
+		Suppressed map[string]uint64
+	}{
+		{
+			Addr:       "127.0.0.1:55525",
+			Limit:      syslog.RateLimit{SourceRate: 1, SourceBurst: 5, Interval: 100 * time.Millisecond},
+			Passed:     8,
+			Suppressed: map[string]uint64{"127.0.0.2": 15},
+		},
+		{
+			Addr:       "127.0.0.1:55526",
+			Limit:      syslog.RateLimit{SubnetRate: 1, SubnetBurst: 4, Interval: 100 * time.Millisecond},
+			Passed:     4,
+			Suppressed: map[string]uint64{"127.0.0.2": 16, "127.0.0.3": 3},
+		},
+	}
+	for _, tt := range tests {
+		lsn, err := syslog.NewListener(tt.Addr, syslog.Options{BufSize: 1500, Workers: 1, RateLimit: tt.Limit}, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := make(chan *pb.Event)
+		go lsn.Listen(ch)
+
+		// Первый отправитель "штормит", второй - отправляет несколько сообщений.
+		conns := dialSources(t, tt.Addr, 2)
+		for n := 1; n <= 20; n++ {
+			fmt.Fprintf(conns[0], "port %d change link state to down", n)
+		}
+		for n := 1; n <= 3; n++ {
+			fmt.Fprintf(conns[1], "port %d change link state to down", n)
+		}
+		for _, conn := range conns {
+			conn.Close()
+		}
+
+		passed, suppressed := 0, make(map[string]uint64)
+		for len(suppressed) < len(tt.Suppressed) {
+			select {
+			case event := <-ch:
+				if event.Type == pb.EventType_StormSuppressed {
+					suppressed[event.Host] += event.Suppressed
+				} else {
+					passed++
+				}
+			case <-time.After(time.Second):
+				t.Fatal("unexpected result - storm events are not received", tt.Addr, lsn.Counters())
+			}
+		}
+		if passed != tt.Passed || fmt.Sprint(suppressed) != fmt.Sprint(tt.Suppressed) ||
+			fmt.Sprint(lsn.Suppressed()) != fmt.Sprint(tt.Suppressed) {
+			t.Fatal("unexpected result - rate limit not match with criterias", tt.Addr, passed, suppressed, lsn.Suppressed())
+		}
+		if c := lsn.Counters(); c.Limited != uint64(23-tt.Passed) {
+			t.Fatal("unexpected result - counters not match with criterias", c)
+		}
+		lsn.Close()
+	}
+}
+
+func TestListenerRateLimitSources(t *testing.T) {
+	p, err := parser.NewParser([]string{"link_down ~ port $device_port$ change link state to down"}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lsn, err := syslog.NewListener("127.0.0.1:55533", syslog.Options{
+		BufSize:    1500,
+		Workers:    1,
+		ReadBuffer: 4 << 20,
+		RateLimit:  syslog.RateLimit{SourceRate: 0.001, SourceBurst: 1, Interval: time.Second},
+	}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+	storms := make(chan *pb.Event, 2000)
+	go func() {
+		for event := range ch {
+			if event.Type == pb.EventType_StormSuppressed && len(event.Host) == 0 {
+				storms <- event
+			}
+		}
+	}()
+
+	// Количество отправителей в состояниях ограничителя и счетчиках отброшенных сообщений
+	// ограничено, остальные отправители используют общий запас и общий счетчик
+	// (отправители сверх 1024 делят один запас - проходит только первое их сообщение).
+	const sources = 1100
+	conns := dialSources(t, "127.0.0.1:55533", sources)
+	for _, conn := range conns {
+		fmt.Fprint(conn, "port 1 change link state to down")
+		fmt.Fprint(conn, "port 2 change link state to down")
+		conn.Close()
+	}
+	for start := time.Now(); time.Since(start) < time.Second && lsn.Counters().Recv < 2*sources; {
+		time.Sleep(10 * time.Millisecond)
+	}
+	suppressed := lsn.Suppressed()
+	const other = 2*(sources-1024) - 1
+	if c := lsn.Counters(); c.Recv != 2*sources || c.Limited != 1024+other {
+		t.Fatal("unexpected result - counters not match with criterias", c)
+	}
+	if len(suppressed) != 1025 || suppressed[syslog.SuppressedOther] != other || suppressed["127.0.0.2"] != 1 {
+		t.Fatal("unexpected result - suppressed sources not match with criterias", len(suppressed), suppressed[syslog.SuppressedOther])
+	}
+
+	// Событие StormSuppressed по общему счетчику не содержит адреса устройства.
+	select {
+	case event := <-storms:
+		if event.Suppressed != other {
+			t.Fatal("unexpected result - storm event not match with criterias", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("unexpected result - no storm event for other sources")
+	}
+}
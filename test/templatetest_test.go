@@ -0,0 +1,97 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neurovillain/syslog-catcher/pkg/service/catcher"
+	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/templatetest"
+)
+
+func TestTemplateTestGolden(t *testing.T) {
+
+	cfg, err := config.ParseFile("../examples/service_config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := catcher.NewListenerParser(cfg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := catcher.NewListenerParser(cfg, len(cfg.Syslog.Listeners)); err == nil {
+		t.Fatal("unexpected result - parser for unknown listener created")
+	}
+
+	samples, err := os.Open("../examples/template_samples.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer samples.Close()
+	results, err := templatetest.Run(p, samples, "127.0.0.1", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Result]++
+		if r.Result == templatetest.ResultMatched && (len(r.Template) == 0 || r.Event.GetTemplate() != r.Template) {
+			t.Fatal("unexpected result - matched template not set in event", r)
+		}
+	}
+	if counts[templatetest.ResultMatched] != 6 || counts[templatetest.ResultUnmatched] != 1 {
+		t.Fatal("unexpected result - results count not match", counts)
+	}
+
+	golden, err := os.Open("../examples/template_samples.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer golden.Close()
+	diff, err := templatetest.CompareGolden(golden, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 0 {
+		t.Fatal("unexpected result - golden mismatch\n", strings.Join(diff, "\n"))
+	}
+
+	buf := &bytes.Buffer{}
+	if err := templatetest.WriteGolden(buf, results); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = templatetest.CompareGolden(bytes.NewReader(buf.Bytes()), results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 0 {
+		t.Fatal("unexpected result - written golden mismatch", diff)
+	}
+
+	// Измененное событие, новый и удаленный примеры дают по одному несовпадению,
+	// остальные записи сопоставляются по тексту сообщения.
+	changed := strings.Replace(buf.String(), `"Port":12`, `"Port":13`, 1)
+	if diff, _ := templatetest.CompareGolden(strings.NewReader(changed), results); len(diff) != 1 {
+		t.Fatal("unexpected result - changed event mismatches", diff)
+	}
+	if diff, _ := templatetest.CompareGolden(bytes.NewReader(buf.Bytes()), results[1:]); len(diff) != 1 || !strings.HasPrefix(diff[0], "missing result") {
+		t.Fatal("unexpected result - removed sample mismatches", diff)
+	}
+	inserted := append([]templatetest.Result{{Line: 1, Text: "inserted sample", Result: templatetest.ResultUnmatched}}, results...)
+	if diff, _ := templatetest.CompareGolden(bytes.NewReader(buf.Bytes()), inserted); len(diff) != 1 || !strings.Contains(diff[0], "unexpected result") {
+		t.Fatal("unexpected result - inserted sample mismatches", diff)
+	}
+	if diff, _ := templatetest.CompareGolden(strings.NewReader(""), results); len(diff) != len(results) {
+		t.Fatal("unexpected result - empty golden mismatches", diff)
+	}
+	if _, err := templatetest.CompareGolden(strings.NewReader("not json\n"), results); err == nil {
+		t.Fatal("unexpected result - invalid golden accepted")
+	}
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/config"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+)
+
+// newCert - выпустить сертификат с указанным именем, подписанный parent (nil - самоподписанный).
+func newCert(t *testing.T, name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, interface{}(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestTLSListener(t *testing.T) {
+	ca := newCert(t, "devices-ca", nil)
+	server := newCert(t, "catcher", &ca)
+	device := newCert(t, "switch01", &ca)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	p, err := parser.NewParser(patterns, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:55517"
+	lsn, err := syslog.NewTLSListener(addr, &tls.Config{
+		Certificates: []tls.Certificate{server},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}, syslog.Options{BufSize: 1500, IdleTimeout: time.Second, Location: time.UTC}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+	ch := make(chan *pb.Event)
+	go lsn.Listen(ch)
+
+	// Устройство без сертификата не может передать сообщение.
+	if conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: pool, ServerName: "catcher"}); err == nil {
+		conn.Write([]byte("192.168.1.104 - - - port 6 change link state to down\n"))
+		conn.Close()
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		RootCAs:      pool,
+		ServerName:   "catcher",
+		Certificates: []tls.Certificate{device},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	msg := "<34>1 - - - - - - 192.168.1.105 - - - port 7 change link state to down"
+	if _, err := fmt.Fprintf(conn, "%d %s", len(msg), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-ch:
+		if event.Port != 7 || event.Peer != "switch01" {
+			t.Fatal("unexpected result - event not match with criterias", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unexpected result - no event for tls message")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catcher-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	server := newCert(t, "catcher", nil)
+	key, err := x509.MarshalECPrivateKey(server.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]*pem.Block{
+		"cert.pem": {Type: "CERTIFICATE", Bytes: server.Certificate[0]},
+		"key.pem":  {Type: "EC PRIVATE KEY", Bytes: key},
+	}
+	for name, block := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Сертификат устройства требуется только с проверкой - иначе устройство не удостоверено.
+	cfg := config.TLS{Cert: filepath.Join(dir, "cert.pem"), Key: filepath.Join(dir, "key.pem"), RequireClientCert: true}
+	if _, err := cfg.Config(); err == nil {
+		t.Fatal("unexpected result - client cert without verification is accepted")
+	}
+	cfg.VerifyClientCert = true
+	result, err := cfg.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatal("unexpected result - client auth not match with criterias", result.ClientAuth)
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	pb "github.com/neurovillain/syslog-catcher/pkg/api/proto"
+	"github.com/neurovillain/syslog-catcher/pkg/service/parser"
+	"github.com/neurovillain/syslog-catcher/pkg/service/syslog"
+)
+
+func TestUnixListener(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported")
+	}
+	dir, err := ioutil.TempDir("", "catcher-unix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := parser.NewParser([]string{"link_down ~ port $device_port$ change link state to down"}, parser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		path := filepath.Join(dir, network+".sock")
+		// Файл сокета, оставшийся от предыдущего запуска.
+		stale, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stale.Close()
+
+		lsn, err := syslog.NewUnixListener(network, path, 0660, syslog.Options{BufSize: 1500}, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := make(chan *pb.Event)
+		go lsn.Listen(ch)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&os.ModePerm != 0660 {
+			t.Fatal("unexpected result - socket mode not match with criterias", info.Mode())
+		}
+
+		conn, err := net.Dial(network, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(conn, "<30>Oct 11 22:14:15 collector lldpd[812]: port 5 change link state to down\n")
+		select {
+		case event := <-ch:
+			if event.Host != "127.0.0.1" || event.Port != 5 || event.Header.GetAppName() != "lldpd" {
+				t.Fatal("unexpected result - event not match with criterias", network, event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("unexpected result - event is not received", network, lsn.Counters())
+		}
+		conn.Close()
+		lsn.Close()
+	}
+}